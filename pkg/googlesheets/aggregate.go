@@ -0,0 +1,317 @@
+package googlesheets
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	aggregationSum   = "sum"
+	aggregationAvg   = "avg"
+	aggregationMin   = "min"
+	aggregationMax   = "max"
+	aggregationCount = "count"
+	aggregationFirst = "first"
+	aggregationLast  = "last"
+)
+
+// rowGroup is a group of frame rows sharing the same group by values.
+type rowGroup struct {
+	values []any
+	rows   []int
+}
+
+// aggregateQueryFrame applies the group by and aggregations of the query to the frame, with the time buckets
+// aligned in the given location.
+func aggregateQueryFrame(frame *data.Frame, qm *models.QueryModel, loc *time.Location) (*data.Frame, error) {
+	var interval time.Duration
+	if qm.GroupByInterval != "" {
+		var err error
+		interval, err = gtime.ParseDuration(qm.GroupByInterval)
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid group by interval %q: %w", qm.GroupByInterval, err))
+		}
+	}
+	return aggregateFrame(frame, qm.GroupBy, interval, loc, qm.Aggregations)
+}
+
+// getDownsampleInterval returns the size of the time buckets of a downsampled query: the query
+// interval, unless it would return more than MaxDataPoints over the time range.
+func getDownsampleInterval(qm *models.QueryModel) time.Duration {
+	interval := qm.Interval
+	if qm.MaxDataPoints > 0 {
+		interval = max(interval, qm.TimeRange.Duration()/time.Duration(qm.MaxDataPoints))
+	}
+	return interval
+}
+
+// downsampleFrame aggregates the rows of the frame into buckets of its first time field. Number columns
+// are aggregated with the function set in the query, avg by default, and other columns keep their last value.
+// The buckets are aligned in the given location.
+func downsampleFrame(frame *data.Frame, qm *models.QueryModel, loc *time.Location) (*data.Frame, error) {
+	timeIndex := findTimeField(frame)
+	interval := getDownsampleInterval(qm)
+	if timeIndex < 0 || interval <= 0 {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.Notices = append(frame.Meta.Notices, data.Notice{
+			Severity: data.NoticeSeverityInfo,
+			Text:     "Downsampling skipped: it requires a time column and an interval",
+		})
+		return frame, nil
+	}
+
+	aggregations := make([]models.AggregationModel, 0, len(frame.Fields)-1)
+	for i, field := range frame.Fields {
+		if i == timeIndex {
+			continue
+		}
+		function := aggregationLast
+		if field.Type().NonNullableType() == data.FieldTypeFloat64 {
+			function = aggregationAvg
+			if f, ok := qm.DownsampleFunctions[field.Name]; ok {
+				function = strings.ToLower(f)
+			}
+		}
+		aggregations = append(aggregations, models.AggregationModel{Column: field.Name, Function: function, Alias: field.Name})
+	}
+
+	downsampled, err := aggregateFrame(frame, []string{frame.Fields[timeIndex].Name}, interval, loc, aggregations)
+	if err != nil {
+		return nil, err
+	}
+	if downsampled.Meta == nil {
+		downsampled.Meta = &data.FrameMeta{}
+	}
+	if meta, ok := downsampled.Meta.Custom.(map[string]any); ok {
+		meta["downsampleInterval"] = interval.String()
+	}
+	return downsampled, nil
+}
+
+// aggregateFrame groups the rows of the frame by the values of the group by columns, with the
+// time columns truncated to the interval in the given location when it is set, and computes the aggregations
+// of each group. Without group by columns the aggregations are computed over all the rows.
+func aggregateFrame(frame *data.Frame, groupBy []string, interval time.Duration, loc *time.Location, aggregations []models.AggregationModel) (*data.Frame, error) {
+	groupFields := make([]*data.Field, 0, len(groupBy))
+	for _, column := range groupBy {
+		field, fieldIndex := frame.FieldByName(column)
+		if fieldIndex < 0 {
+			return nil, backend.DownstreamError(fmt.Errorf("unknown group by column %q", column))
+		}
+		groupFields = append(groupFields, field)
+	}
+
+	aggregationFields := make([]*data.Field, 0, len(aggregations))
+	for _, aggregation := range aggregations {
+		field, fieldIndex := frame.FieldByName(aggregation.Column)
+		if fieldIndex < 0 {
+			return nil, backend.DownstreamError(fmt.Errorf("unknown aggregation column %q", aggregation.Column))
+		}
+		if err := validateAggregation(field, aggregation); err != nil {
+			return nil, backend.DownstreamError(err)
+		}
+		aggregationFields = append(aggregationFields, field)
+	}
+
+	groups := groupRows(frame.Rows(), groupFields, interval, loc)
+
+	fields := make([]*data.Field, 0, len(groupFields)+len(aggregationFields))
+	for i, groupField := range groupFields {
+		field := data.NewFieldFromFieldType(groupField.Type().NullableType(), len(groups))
+		field.Name = groupField.Name
+		field.Config = groupField.Config
+		for groupIndex, group := range groups {
+			if group.values[i] != nil {
+				field.SetConcrete(groupIndex, group.values[i])
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	for i, aggregation := range aggregations {
+		sourceField := aggregationFields[i]
+		function := strings.ToLower(aggregation.Function)
+		fieldType := sourceField.Type().NullableType()
+		if function == aggregationSum || function == aggregationAvg || function == aggregationCount {
+			fieldType = data.FieldTypeNullableFloat64
+		}
+
+		field := data.NewFieldFromFieldType(fieldType, len(groups))
+		field.Name = aggregation.Alias
+		if field.Name == "" {
+			field.Name = fmt.Sprintf("%s (%s)", aggregation.Column, function)
+		}
+		if function != aggregationCount && sourceField.Config != nil {
+			field.Config = &data.FieldConfig{Unit: sourceField.Config.Unit}
+		}
+		for groupIndex, group := range groups {
+			if value := aggregate(sourceField, group.rows, function); value != nil {
+				field.SetConcrete(groupIndex, value)
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	aggregated := data.NewFrame(frame.Name, fields...)
+	aggregated.RefID = frame.RefID
+	aggregated.Meta = frame.Meta
+	return aggregated, nil
+}
+
+// validateAggregation checks that the aggregation function applies to the field.
+func validateAggregation(field *data.Field, aggregation models.AggregationModel) error {
+	switch strings.ToLower(aggregation.Function) {
+	case aggregationSum, aggregationAvg:
+		if field.Type().NonNullableType() != data.FieldTypeFloat64 {
+			return fmt.Errorf("%s aggregation of column %q requires a number column", aggregation.Function, aggregation.Column)
+		}
+	case aggregationMin, aggregationMax, aggregationCount, aggregationFirst, aggregationLast:
+	default:
+		return fmt.Errorf("unknown aggregation function %q", aggregation.Function)
+	}
+	return nil
+}
+
+// groupRows returns the groups of rows sharing the same values in the group fields, sorted by these values.
+// Without group fields, there is always a single group, even without rows, so that a count of no rows is 0.
+func groupRows(rowCount int, groupFields []*data.Field, interval time.Duration, loc *time.Location) []*rowGroup {
+	if len(groupFields) == 0 {
+		group := &rowGroup{values: []any{}, rows: make([]int, rowCount)}
+		for row := range group.rows {
+			group.rows[row] = row
+		}
+		return []*rowGroup{group}
+	}
+
+	groupsByKey := map[string]*rowGroup{}
+	groups := []*rowGroup{}
+	for row := 0; row < rowCount; row++ {
+		values := make([]any, len(groupFields))
+		var key strings.Builder
+		for i, field := range groupFields {
+			value, ok := cellValue(field.At(row))
+			if !ok {
+				key.WriteString("\x00")
+				continue
+			}
+			if t, isTime := value.(time.Time); isTime && interval > 0 {
+				value = truncateTime(t, interval, loc)
+			}
+			values[i] = value
+			key.WriteString(formatCellValue(value))
+			key.WriteString("\x1f")
+		}
+
+		group, ok := groupsByKey[key.String()]
+		if !ok {
+			group = &rowGroup{values: values}
+			groupsByKey[key.String()] = group
+			groups = append(groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+
+	slices.SortStableFunc(groups, func(a, b *rowGroup) int {
+		for i := range a.values {
+			if c := compareCellValues(a.values[i], b.values[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return groups
+}
+
+// truncateTime truncates a time to a multiple of the interval in the given location. Buckets of whole days start
+// at local midnight, counting days from the Unix epoch, and shorter buckets are aligned on the local wall clock.
+func truncateTime(t time.Time, interval time.Duration, loc *time.Location) time.Time {
+	t = t.In(loc)
+	const day = 24 * time.Hour
+	if interval%day == 0 {
+		days := int64(interval / day)
+		// The number of days since the epoch of the local date, which doesn't depend on the offset
+		dayNumber := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+		offset := ((dayNumber % days) + days) % days
+		return time.Date(t.Year(), t.Month(), t.Day()-int(offset), 0, 0, 0, 0, loc)
+	}
+
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(interval).Add(-shift)
+}
+
+// aggregate computes the aggregation function over the non-null values of the rows of the field.
+// It returns nil when there is no value to aggregate.
+func aggregate(field *data.Field, rows []int, function string) any {
+	values := make([]any, 0, len(rows))
+	for _, row := range rows {
+		if value, ok := cellValue(field.At(row)); ok {
+			values = append(values, value)
+		}
+	}
+
+	switch function {
+	case aggregationCount:
+		return float64(len(values))
+	case aggregationSum, aggregationAvg:
+		if len(values) == 0 {
+			return nil
+		}
+		sum := 0.0
+		for _, value := range values {
+			sum += value.(float64)
+		}
+		if function == aggregationAvg {
+			return sum / float64(len(values))
+		}
+		return sum
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	switch function {
+	case aggregationMin:
+		return slices.MinFunc(values, compareCellValues)
+	case aggregationMax:
+		return slices.MaxFunc(values, compareCellValues)
+	case aggregationFirst:
+		return values[0]
+	default:
+		return values[len(values)-1]
+	}
+}
+
+// compareCellValues compares two cell values of the same type, null values being the lowest.
+func compareCellValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch v := a.(type) {
+	case float64:
+		return cmp.Compare(v, b.(float64))
+	case time.Time:
+		return v.Compare(b.(time.Time))
+	case bool:
+		return compareBool(v, b.(bool))
+	default:
+		return strings.Compare(formatCellValue(a), formatCellValue(b))
+	}
+}
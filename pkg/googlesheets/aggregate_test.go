@@ -0,0 +1,224 @@
+package googlesheets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransactionFrame() *data.Frame {
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2026, time.March, day, hour, 30, 0, 0, time.UTC)
+		return &t
+	}
+	str := func(s string) *string { return &s }
+	num := func(f float64) *float64 { return &f }
+
+	return data.NewFrame("transactions",
+		data.NewField("Time", nil, []*time.Time{at(1, 9), at(1, 10), at(1, 10), at(2, 9), at(2, 9)}),
+		data.NewField("Category", nil, []*string{str("Food"), str("Rent"), str("Food"), nil, str("Food")}),
+		data.NewField("Amount", nil, []*float64{num(10), num(1000), num(20), num(5), nil}).SetConfig(&data.FieldConfig{Unit: "currencyEUR"}),
+	)
+}
+
+func TestAggregateFrame(t *testing.T) {
+	t.Run("group by column", func(t *testing.T) {
+		frame, err := aggregateFrame(newTestTransactionFrame(), []string{"Category"}, 0, time.UTC, []models.AggregationModel{
+			{Column: "Amount", Function: "sum"},
+			{Column: "Amount", Function: "count", Alias: "Transactions"},
+			{Column: "Time", Function: "last"},
+		})
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 4)
+		require.Equal(t, 3, frame.Rows())
+
+		assert.Equal(t, "Category", frame.Fields[0].Name)
+		assert.Equal(t, "Amount (sum)", frame.Fields[1].Name)
+		assert.Equal(t, "currencyEUR", frame.Fields[1].Config.Unit)
+		assert.Equal(t, "Transactions", frame.Fields[2].Name)
+		assert.Equal(t, data.FieldTypeNullableTime, frame.Fields[3].Type())
+
+		// Groups are sorted by value, null first
+		assert.Nil(t, frame.Fields[0].At(0))
+		assert.Equal(t, "Food", *frame.Fields[0].At(1).(*string))
+		assert.Equal(t, "Rent", *frame.Fields[0].At(2).(*string))
+
+		assert.Equal(t, 5.0, *frame.Fields[1].At(0).(*float64))
+		assert.Equal(t, 30.0, *frame.Fields[1].At(1).(*float64))
+		assert.Equal(t, 1000.0, *frame.Fields[1].At(2).(*float64))
+
+		// Null values are not counted
+		assert.Equal(t, 2.0, *frame.Fields[2].At(1).(*float64))
+		assert.Equal(t, 2, frame.Fields[3].At(1).(*time.Time).Day())
+	})
+
+	t.Run("group by time bucket", func(t *testing.T) {
+		frame, err := aggregateQueryFrame(newTestTransactionFrame(), &models.QueryModel{
+			GroupBy:         []string{"Time"},
+			GroupByInterval: "1d",
+			Aggregations:    []models.AggregationModel{{Column: "Amount", Function: "avg"}, {Column: "Amount", Function: "max"}},
+		}, time.UTC)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+
+		assert.Equal(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), *frame.Fields[0].At(0).(*time.Time))
+		assert.Equal(t, 1030.0/3, *frame.Fields[1].At(0).(*float64))
+		assert.Equal(t, 1000.0, *frame.Fields[2].At(0).(*float64))
+		assert.Equal(t, 5.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("time buckets are aligned in the location", func(t *testing.T) {
+		// 2026-03-01 09:30 UTC is 2026-03-01 18:30 in Tokyo, and 2026-03-02 09:30 UTC is 2026-03-02 18:30
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		frame, err := aggregateQueryFrame(newTestTransactionFrame(), &models.QueryModel{
+			GroupBy:         []string{"Time"},
+			GroupByInterval: "1d",
+			Aggregations:    []models.AggregationModel{{Column: "Amount", Function: "count"}},
+		}, tokyo)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		assert.True(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, tokyo).Equal(*frame.Fields[0].At(0).(*time.Time)))
+		assert.True(t, time.Date(2026, time.March, 2, 0, 0, 0, 0, tokyo).Equal(*frame.Fields[0].At(1).(*time.Time)))
+	})
+
+	t.Run("aggregations without group by", func(t *testing.T) {
+		frame, err := aggregateFrame(newTestTransactionFrame(), nil, 0, time.UTC, []models.AggregationModel{
+			{Column: "Amount", Function: "min"},
+			{Column: "Category", Function: "first"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, 5.0, *frame.Fields[0].At(0).(*float64))
+		assert.Equal(t, "Food", *frame.Fields[1].At(0).(*string))
+	})
+
+	t.Run("aggregations without group by nor rows", func(t *testing.T) {
+		// Alerts on the count see 0 rather than no data
+		empty := newTestTransactionFrame()
+		for _, field := range empty.Fields {
+			for field.Len() > 0 {
+				field.Delete(0)
+			}
+		}
+		frame, err := aggregateFrame(empty, nil, 0, time.UTC, []models.AggregationModel{
+			{Column: "Amount", Function: "count"},
+			{Column: "Amount", Function: "sum"},
+			{Column: "Amount", Function: "avg"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, 0.0, *frame.Fields[0].At(0).(*float64))
+		assert.Nil(t, frame.Fields[1].At(0))
+		assert.Nil(t, frame.Fields[2].At(0))
+	})
+
+	t.Run("invalid aggregations are downstream errors", func(t *testing.T) {
+		for _, qm := range []*models.QueryModel{
+			{GroupBy: []string{"Unknown"}},
+			{Aggregations: []models.AggregationModel{{Column: "Unknown", Function: "sum"}}},
+			{Aggregations: []models.AggregationModel{{Column: "Amount", Function: "median"}}},
+			{Aggregations: []models.AggregationModel{{Column: "Category", Function: "sum"}}},
+			{GroupBy: []string{"Time"}, GroupByInterval: "often"},
+		} {
+			_, err := aggregateQueryFrame(newTestTransactionFrame(), qm, time.UTC)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		}
+	})
+}
+
+func TestTruncateTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := time.Date(2026, time.March, 4, 22, 40, 0, 0, newYork)
+
+	assert.True(t, time.Date(2026, time.March, 4, 0, 0, 0, 0, newYork).Equal(truncateTime(at, 24*time.Hour, newYork)))
+	assert.True(t, time.Date(2026, time.March, 4, 21, 0, 0, 0, newYork).Equal(truncateTime(at, 3*time.Hour, newYork)))
+	assert.True(t, time.Date(2026, time.March, 4, 22, 30, 0, 0, newYork).Equal(truncateTime(at, 15*time.Minute, newYork)))
+	// Buckets of several days are counted from the epoch, so that they don't depend on the data
+	week := truncateTime(at, 7*24*time.Hour, newYork)
+	assert.Equal(t, week, truncateTime(week.AddDate(0, 0, 6), 7*24*time.Hour, newYork))
+	assert.Equal(t, 0, week.Hour())
+}
+
+func TestDownsampleFrame(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]*time.Time, 0, 60)
+	values := make([]*float64, 0, 60)
+	sensors := make([]*string, 0, 60)
+	for i := 0; i < 60; i++ {
+		ts := from.Add(time.Duration(i) * time.Minute)
+		value := float64(i)
+		sensor := "sensor-1"
+		times = append(times, &ts)
+		values = append(values, &value)
+		sensors = append(sensors, &sensor)
+	}
+	newFrame := func() *data.Frame {
+		frame := data.NewFrame("readings",
+			data.NewField("Sensor", nil, sensors),
+			data.NewField("Time", nil, times),
+			data.NewField("Value", nil, values),
+		)
+		frame.Meta = &data.FrameMeta{Custom: map[string]any{}}
+		return frame
+	}
+	timeRange := backend.TimeRange{From: from, To: from.Add(time.Hour)}
+
+	t.Run("buckets are sized from max data points", func(t *testing.T) {
+		qm := &models.QueryModel{Downsample: true, TimeRange: timeRange, MaxDataPoints: 4, Interval: time.Minute}
+		assert.Equal(t, 15*time.Minute, getDownsampleInterval(qm))
+
+		frame, err := downsampleFrame(newFrame(), qm, time.UTC)
+		require.NoError(t, err)
+		require.Equal(t, 4, frame.Rows())
+		assert.Equal(t, []string{"Time", "Sensor", "Value"}, []string{frame.Fields[0].Name, frame.Fields[1].Name, frame.Fields[2].Name})
+		assert.Equal(t, from.Add(15*time.Minute), *frame.Fields[0].At(1).(*time.Time))
+		assert.Equal(t, "sensor-1", *frame.Fields[1].At(1).(*string))
+		assert.Equal(t, 7.0, *frame.Fields[2].At(0).(*float64))
+		assert.Equal(t, "15m0s", frame.Meta.Custom.(map[string]any)["downsampleInterval"])
+	})
+
+	t.Run("buckets are at least the query interval", func(t *testing.T) {
+		qm := &models.QueryModel{Downsample: true, TimeRange: timeRange, MaxDataPoints: 1000, Interval: 30 * time.Minute}
+		assert.Equal(t, 30*time.Minute, getDownsampleInterval(qm))
+	})
+
+	t.Run("aggregation function can be set per column", func(t *testing.T) {
+		qm := &models.QueryModel{Downsample: true, TimeRange: timeRange, MaxDataPoints: 2, DownsampleFunctions: map[string]string{"Value": "max"}}
+		frame, err := downsampleFrame(newFrame(), qm, time.UTC)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		assert.Equal(t, 29.0, *frame.Fields[2].At(0).(*float64))
+		assert.Equal(t, 59.0, *frame.Fields[2].At(1).(*float64))
+	})
+
+	t.Run("buckets are aligned in the location", func(t *testing.T) {
+		// India is 5:30 ahead of UTC, so that hourly buckets start at half past the UTC hour
+		kolkata, err := time.LoadLocation("Asia/Kolkata")
+		require.NoError(t, err)
+		qm := &models.QueryModel{Downsample: true, TimeRange: timeRange, Interval: time.Hour}
+		frame, err := downsampleFrame(newFrame(), qm, kolkata)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		assert.True(t, from.Add(-30*time.Minute).Equal(*frame.Fields[0].At(0).(*time.Time)))
+		assert.True(t, from.Add(30*time.Minute).Equal(*frame.Fields[0].At(1).(*time.Time)))
+		assert.Equal(t, 14.5, *frame.Fields[2].At(0).(*float64))
+	})
+
+	t.Run("frames without time field are returned as is", func(t *testing.T) {
+		frame := data.NewFrame("readings", data.NewField("Value", nil, values))
+		qm := &models.QueryModel{Downsample: true, TimeRange: timeRange, MaxDataPoints: 2}
+		downsampled, err := downsampleFrame(frame, qm, time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, 60, downsampled.Rows())
+		assert.Len(t, downsampled.Meta.Notices, 1)
+	})
+}
@@ -0,0 +1,190 @@
+package googlesheets
+
+import (
+	"container/list"
+	"sync"
+	"time"
+	"unsafe"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Cache stores the data fetched from the Google API. It is implemented by go-cache, which is unbounded,
+// by the LRU cache bounding the memory of the cached data, and by the disk cache in front of either.
+type Cache interface {
+	Get(key string) (any, bool)
+	GetWithExpiration(key string) (any, time.Time, bool)
+	Set(key string, value any, d time.Duration)
+	ItemCount() int
+	Flush()
+}
+
+// defaultEntrySize is the estimated size of the cached values of an unknown type.
+const defaultEntrySize = 1024
+
+// lruCache is a cache bounded by an estimate of the bytes of its values. It evicts the least recently used
+// values to stay within its budget.
+type lruCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	items    map[string]*list.Element
+	// order holds the entries, the most recently used first
+	order *list.List
+}
+
+type lruEntry struct {
+	key     string
+	value   any
+	size    int64
+	expires time.Time
+}
+
+// newLRUCache returns a cache holding values up to the given estimate of bytes.
+func newLRUCache(maxBytes int64) *lruCache {
+	return &lruCache{
+		maxBytes: maxBytes,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) Get(key string) (any, bool) {
+	value, _, found := c.GetWithExpiration(key)
+	return value, found
+}
+
+// GetWithExpiration returns the value of a key and its expiration, which is the zero time for values that
+// don't expire.
+func (c *lruCache) GetWithExpiration(key string) (any, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		cacheMisses.Inc()
+		return nil, time.Time{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(element)
+		cacheMisses.Inc()
+		return nil, time.Time{}, false
+	}
+
+	c.order.MoveToFront(element)
+	cacheHits.Inc()
+	return entry.value, entry.expires, true
+}
+
+// Set stores a value, then evicts the least recently used values until the cache is within its budget.
+// Values set with a zero or negative duration don't expire, and values larger than the whole budget are not stored.
+func (c *lruCache) Set(key string, value any, d time.Duration) {
+	entry := &lruEntry{key: key, value: value, size: estimateSize(value)}
+	if d > 0 {
+		entry.expires = time.Now().Add(d)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	if entry.size > c.maxBytes {
+		cacheEvictions.Inc()
+		return
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+	c.bytes += entry.size
+	cacheBytes.Add(float64(entry.size))
+	for c.bytes > c.maxBytes {
+		c.remove(c.order.Back())
+		cacheEvictions.Inc()
+	}
+}
+
+func (c *lruCache) ItemCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Flush removes all the values.
+func (c *lruCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for element := c.order.Front(); element != nil; element = c.order.Front() {
+		c.remove(element)
+	}
+}
+
+func (c *lruCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+	cacheBytes.Sub(float64(entry.size))
+}
+
+// estimateSize estimates the bytes held by a cached value. The spreadsheet data is counted cell by cell,
+// as it makes most of the cached bytes.
+func estimateSize(value any) int64 {
+	switch v := value.(type) {
+	case *spreadsheetData:
+		size := int64(unsafe.Sizeof(*v)) + int64(len(v.TimeZone)+len(v.ModifiedTime))
+		for _, grid := range v.Grids {
+			size += int64(unsafe.Sizeof(*grid)) + int64(len(grid.Title)+len(grid.NamedRange)+len(grid.ResolvedRange))
+			if grid.Data != nil {
+				size += estimateGridDataSize(grid.Data)
+			}
+		}
+		return size
+	case []SheetInfo:
+		size := int64(len(v)) * int64(unsafe.Sizeof(SheetInfo{}))
+		for _, info := range v {
+			size += int64(len(info.Title))
+		}
+		return size
+	case *drive.File:
+		return int64(unsafe.Sizeof(*v)) + int64(len(v.Id)+len(v.Name))
+	case string:
+		return int64(len(v))
+	default:
+		return defaultEntrySize
+	}
+}
+
+func estimateGridDataSize(gridData *sheets.GridData) int64 {
+	size := int64(unsafe.Sizeof(*gridData))
+	for _, row := range gridData.RowData {
+		size += int64(unsafe.Sizeof(*row))
+		for _, cell := range row.Values {
+			if cell != nil {
+				size += estimateCellSize(cell)
+			}
+		}
+	}
+	return size
+}
+
+func estimateCellSize(cell *sheets.CellData) int64 {
+	size := int64(unsafe.Sizeof(*cell)) + int64(len(cell.FormattedValue))
+	if cell.EffectiveValue != nil {
+		size += int64(unsafe.Sizeof(*cell.EffectiveValue))
+		if cell.EffectiveValue.StringValue != nil {
+			size += int64(len(*cell.EffectiveValue.StringValue))
+		}
+	}
+	for _, format := range []*sheets.CellFormat{cell.EffectiveFormat, cell.UserEnteredFormat} {
+		if format == nil {
+			continue
+		}
+		size += int64(unsafe.Sizeof(*format))
+		if format.NumberFormat != nil {
+			size += int64(unsafe.Sizeof(*format.NumberFormat)) + int64(len(format.NumberFormat.Pattern))
+		}
+	}
+	return size
+}
@@ -0,0 +1,108 @@
+package googlesheets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	t.Run("least recently used values are evicted", func(t *testing.T) {
+		c := newLRUCache(10)
+		before := getCounterValue(t, cacheEvictions)
+
+		c.Set("a", "aaaa", time.Minute)
+		c.Set("b", "bbbb", time.Minute)
+		_, found := c.Get("a")
+		require.True(t, found)
+		c.Set("c", "cccc", time.Minute)
+
+		_, found = c.Get("b")
+		assert.False(t, found)
+		value, found := c.Get("a")
+		assert.True(t, found)
+		assert.Equal(t, "aaaa", value)
+		_, found = c.Get("c")
+		assert.True(t, found)
+		assert.Equal(t, 2, c.ItemCount())
+		assert.Equal(t, int64(8), c.bytes)
+		assert.Equal(t, float64(1), getCounterValue(t, cacheEvictions)-before)
+	})
+
+	t.Run("values larger than the budget are not stored", func(t *testing.T) {
+		c := newLRUCache(10)
+		c.Set("a", "aaaa", time.Minute)
+		c.Set("b", "bbbbbbbbbbbb", time.Minute)
+
+		_, found := c.Get("b")
+		assert.False(t, found)
+		_, found = c.Get("a")
+		assert.True(t, found)
+	})
+
+	t.Run("replaced values are accounted once", func(t *testing.T) {
+		c := newLRUCache(10)
+		c.Set("a", "aaaa", time.Minute)
+		c.Set("a", "aaaaaa", time.Minute)
+
+		assert.Equal(t, 1, c.ItemCount())
+		assert.Equal(t, int64(6), c.bytes)
+	})
+
+	t.Run("expired values are misses", func(t *testing.T) {
+		c := newLRUCache(10)
+		hits, misses := getCounterValue(t, cacheHits), getCounterValue(t, cacheMisses)
+
+		c.Set("a", "aaaa", time.Minute)
+		c.Set("b", "bbbb", time.Nanosecond)
+		time.Sleep(time.Millisecond)
+
+		_, expires, found := c.GetWithExpiration("a")
+		assert.True(t, found)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expires, time.Second)
+		_, found = c.Get("b")
+		assert.False(t, found)
+		assert.Equal(t, 1, c.ItemCount())
+		assert.Equal(t, float64(1), getCounterValue(t, cacheHits)-hits)
+		assert.Equal(t, float64(1), getCounterValue(t, cacheMisses)-misses)
+	})
+
+	t.Run("flush removes all the values", func(t *testing.T) {
+		c := newLRUCache(10)
+		c.Set("a", "aaaa", time.Minute)
+		c.Set("b", "bbbb", 0)
+		c.Flush()
+
+		assert.Equal(t, 0, c.ItemCount())
+		assert.Equal(t, int64(0), c.bytes)
+	})
+}
+
+func TestEstimateSize(t *testing.T) {
+	small := &spreadsheetData{Grids: []*sheetGrid{{Title: "Sheet1", Data: newTestGrid([]string{"Date", "Value"})}}}
+	large := &spreadsheetData{Grids: []*sheetGrid{{Title: "Sheet1", Data: newTestGrid(
+		[]string{"Date", "Value"},
+		[]string{"2026-01-01", "1"},
+		[]string{"2026-01-02", "2"},
+	)}}}
+
+	assert.Greater(t, estimateSize(large), estimateSize(small))
+	assert.Equal(t, int64(len("My Drive/Reports")), estimateSize("My Drive/Reports"))
+	assert.Equal(t, int64(defaultEntrySize), estimateSize(&RangesInfo{}))
+}
+
+func TestNewCache(t *testing.T) {
+	t.Run("go-cache is the default", func(t *testing.T) {
+		assert.IsType(t, &cache.Cache{}, newCache(backend.DataSourceInstanceSettings{}))
+	})
+
+	t.Run("cache size limit uses the LRU cache", func(t *testing.T) {
+		c := newCache(backend.DataSourceInstanceSettings{JSONData: []byte(`{"cacheSizeLimitMB":64}`)})
+		require.IsType(t, &lruCache{}, c)
+		assert.Equal(t, int64(64<<20), c.(*lruCache).maxBytes)
+	})
+}
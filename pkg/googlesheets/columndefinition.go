@@ -1,6 +1,7 @@
 package googlesheets
 
 import (
+	"fmt"
 	"strings"
 
 	"google.golang.org/api/sheets/v4"
@@ -16,14 +17,27 @@ const (
 	ColumTypeNumber = "NUMBER"
 	// ColumTypeString is the STRING type
 	ColumTypeString = "STRING"
+	// ColumTypeBoolean is the BOOLEAN type. It is never inferred, only forced.
+	ColumTypeBoolean = "BOOLEAN"
 )
 
+// ParseColumnType parses a column type as set in a query, such as "number".
+func ParseColumnType(value string) (ColumnType, error) {
+	switch columnType := ColumnType(strings.ToUpper(strings.TrimSpace(value))); columnType {
+	case ColumTypeTime, ColumTypeNumber, ColumTypeString, ColumTypeBoolean:
+		return columnType, nil
+	default:
+		return "", fmt.Errorf("unknown column type %q", value)
+	}
+}
+
 // ColumnDefinition represents a spreadsheet column definition.
 type ColumnDefinition struct {
 	Header      string
 	ColumnIndex int
 	types       map[ColumnType]bool
 	units       map[string]bool
+	forcedType  ColumnType
 }
 
 // NewColumnDefinition creates a new ColumnDefinition.
@@ -42,8 +56,22 @@ func (cd *ColumnDefinition) CheckCell(cell *sheets.CellData) {
 	cd.checkUnit(cell)
 }
 
+// SetType forces the type of a ColumnDefinition, whatever the types of its cells.
+func (cd *ColumnDefinition) SetType(columnType ColumnType) {
+	cd.forcedType = columnType
+}
+
+// HasForcedType returns whether the type of a ColumnDefinition was forced.
+func (cd *ColumnDefinition) HasForcedType() bool {
+	return cd.forcedType != ""
+}
+
 // GetType gets the type of a ColumnDefinition.
 func (cd *ColumnDefinition) GetType() ColumnType {
+	if cd.HasForcedType() {
+		return cd.forcedType
+	}
+
 	if len(cd.types) == 1 {
 		for columnType := range cd.types {
 			return columnType
@@ -67,7 +95,7 @@ func (cd *ColumnDefinition) GetUnit() string {
 
 // HasMixedTypes returns whether a ColumnDefinition has mixed types.
 func (cd *ColumnDefinition) HasMixedTypes() bool {
-	return len(cd.types) > 1
+	return !cd.HasForcedType() && len(cd.types) > 1
 }
 
 // HasMixedUnits returns whether a ColumnDefinition has mixed units.
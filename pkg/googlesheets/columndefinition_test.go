@@ -140,3 +140,32 @@ func Test_plain_text_cell_number_like_value_is_detected_as_string(t *testing.T)
 	assert.True(t, column.types["STRING"])
 	assert.False(t, column.types["NUMBER"])
 }
+
+func TestParseColumnType(t *testing.T) {
+	for value, expected := range map[string]ColumnType{
+		"time":    ColumTypeTime,
+		"Number":  ColumTypeNumber,
+		"STRING":  ColumTypeString,
+		"boolean": ColumTypeBoolean,
+	} {
+		columnType, err := ParseColumnType(value)
+		require.NoError(t, err)
+		assert.Equal(t, expected, columnType)
+	}
+
+	_, err := ParseColumnType("currency")
+	assert.Error(t, err)
+}
+
+func Test_forced_type_overrides_mixed_types(t *testing.T) {
+	column := NewColumnDefinition("Value", 0)
+	value := 1.0
+	column.CheckCell(&sheets.CellData{FormattedValue: "1", EffectiveValue: &sheets.ExtendedValue{NumberValue: &value}})
+	column.CheckCell(&sheets.CellData{FormattedValue: "n/a"})
+	require.True(t, column.HasMixedTypes())
+
+	column.SetType(ColumTypeNumber)
+
+	assert.Equal(t, ColumnType(ColumTypeNumber), column.GetType())
+	assert.False(t, column.HasMixedTypes())
+}
@@ -3,8 +3,12 @@ package googlesheets
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/grafana/google-sheets-datasource/pkg/models"
@@ -13,13 +17,16 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/patrickmn/go-cache"
 )
 
 var (
-	_ backend.QueryDataHandler    = (*Datasource)(nil)
-	_ backend.CheckHealthHandler  = (*Datasource)(nil)
-	_ backend.CallResourceHandler = (*Datasource)(nil)
+	_ backend.QueryDataHandler      = (*Datasource)(nil)
+	_ backend.CheckHealthHandler    = (*Datasource)(nil)
+	_ backend.CallResourceHandler   = (*Datasource)(nil)
+	_ backend.StreamHandler         = (*Datasource)(nil)
+	_ instancemgmt.InstanceDisposer = (*Datasource)(nil)
 )
 
 type Datasource struct {
@@ -29,18 +36,61 @@ type Datasource struct {
 }
 
 // NewDatasource creates a new Google Sheets datasource instance.
-func NewDatasource(_ context.Context, _ backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+func NewDatasource(_ context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 	ds := &Datasource{
-		googlesheets: &GoogleSheets{Cache: cache.New(300*time.Second, 5*time.Second)},
+		googlesheets: &GoogleSheets{Cache: newCache(settings)},
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/spreadsheets", ds.handleResourceSpreadsheets)
+	mux.HandleFunc("/sheets", ds.handleResourceSheets)
+	mux.HandleFunc("/ranges", ds.handleResourceRanges)
+	mux.HandleFunc("/schema", ds.handleResourceSchema)
+	mux.HandleFunc("/stream-path", ds.handleResourceStreamPath)
 	ds.CallResourceHandler = httpadapter.New(mux)
 
 	return ds, nil
 }
 
+// newCache returns the cache of a datasource instance: an LRU cache when a cache size limit is set, else go-cache,
+// behind which the disk cache of the datasource persists the spreadsheet data when enabled.
+func newCache(settings backend.DataSourceInstanceSettings) Cache {
+	config := models.DatasourceSettings{}
+	if len(settings.JSONData) > 0 {
+		if err := json.Unmarshal(settings.JSONData, &config); err != nil {
+			log.DefaultLogger.Warn("could not read the cache settings, using an unbounded cache", "error", err)
+		}
+	}
+
+	var memory Cache = cache.New(300*time.Second, 5*time.Second)
+	if config.CacheSizeLimitMB > 0 {
+		memory = newLRUCache(int64(config.CacheSizeLimitMB) << 20)
+	}
+	if !config.DiskCache {
+		return memory
+	}
+
+	root := getDiskCacheRoot()
+	dir := getDiskCacheDir(root, settings)
+	removeOtherDiskCacheDirs(dir)
+	disk, err := newDiskCache(memory, dir, config.DiskCacheEncrypt, settings.DecryptedSecureJSONData["diskCacheKey"])
+	if err != nil {
+		log.DefaultLogger.Warn("could not create the disk cache, using the memory cache only", "error", err)
+		return memory
+	}
+	disk.startPruning(root, diskCachePruneInterval)
+	return disk
+}
+
+// Dispose frees the cache when the datasource instance is replaced, such as after its settings changed.
+// The disk cache is kept on disk: it is removed by the instance of the changed settings, if any.
+func (d *Datasource) Dispose() {
+	d.googlesheets.Cache.Flush()
+	if disk, ok := d.googlesheets.Cache.(*diskCache); ok {
+		disk.close()
+	}
+}
+
 // CheckHealth checks if the datasource is working.
 func (d *Datasource) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	logger := backend.Logger.FromContext(ctx)
@@ -95,10 +145,18 @@ func (d *Datasource) QueryData(ctx context.Context, req *backend.QueryDataReques
 			return nil, fmt.Errorf("failed to read query: %w", err)
 		}
 
-		if len(queryModel.Spreadsheet) < 1 {
+		if len(queryModel.Spreadsheet) < 1 && queryModel.QueryType != models.QueryTypeSpreadsheets {
 			continue // not query really exists
 		}
-		dr := d.googlesheets.Query(ctx, q.RefID, queryModel, *config, q.TimeRange)
+		var dr backend.DataResponse
+		switch queryModel.QueryType {
+		case models.QueryTypeVariable:
+			dr = d.googlesheets.VariableQuery(ctx, q.RefID, queryModel, *config, q.TimeRange)
+		case models.QueryTypeSpreadsheets, models.QueryTypeSheets, models.QueryTypeFileInfo:
+			dr = d.googlesheets.MetadataQuery(ctx, q.RefID, queryModel, *config)
+		default:
+			dr = d.googlesheets.Query(ctx, q.RefID, queryModel, *config, q.TimeRange)
+		}
 		if dr.Error != nil {
 			if dr.ErrorSource == backend.ErrorSourceDownstream {
 				// For downstream errors, we log them as warnings as they are not caused by the plugin itself
@@ -113,14 +171,76 @@ func (d *Datasource) QueryData(ctx context.Context, req *backend.QueryDataReques
 	return response, nil
 }
 
+// SubscribeStream allows subscribing to the stream of a query, whose channel path is the spreadsheet followed by a key of the query.
+func (d *Datasource) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if _, err := getStreamQueryModel(req.Path, req.Data); err != nil {
+		log.DefaultLogger.Debug("Invalid stream subscription", "path", req.Path, "error", err)
+		return &backend.SubscribeStreamResponse{Status: backend.SubscribeStreamStatusNotFound}, nil
+	}
+	return &backend.SubscribeStreamResponse{Status: backend.SubscribeStreamStatusOK}, nil
+}
+
+// PublishStream denies publishing, the streams are only fed by the spreadsheets.
+func (d *Datasource) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{Status: backend.PublishStreamStatusPermissionDenied}, nil
+}
+
+// RunStream polls the spreadsheet of a query and sends its frames when they change.
+func (d *Datasource) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	qm, err := getStreamQueryModel(req.Path, req.Data)
+	if err != nil {
+		return err
+	}
+
+	config, err := models.LoadSettings(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	client, err := NewGoogleClient(ctx, *config)
+	if err != nil {
+		return fmt.Errorf("unable to create Google API client: %w", err)
+	}
+
+	return d.googlesheets.runStream(ctx, client, qm, getStreamInterval(qm), func(frame *data.Frame) error {
+		return sender.SendFrame(frame, data.IncludeAll)
+	})
+}
+
+// handleResourceStreamPath returns the channel path of the stream of the query posted, which the subscriptions must use.
+func (d *Datasource) handleResourceStreamPath(rw http.ResponseWriter, req *http.Request) {
+	log.DefaultLogger.Debug("Received resource call", "url", req.URL.String())
+	if req.Method != http.MethodPost {
+		return
+	}
+
+	queryJSON, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeResult(rw, "path", nil, err)
+		return
+	}
+	qm, err := readStreamQueryModel(queryJSON)
+	if err != nil {
+		writeResult(rw, "path", nil, err)
+		return
+	}
+	path, err := getStreamPath(qm)
+	writeResult(rw, "path", path, err)
+}
+
 func writeResult(rw http.ResponseWriter, path string, val any, err error) {
+	writeResults(rw, map[string]any{path: val}, err)
+}
+
+// writeResults writes several values in the response, or the error.
+func writeResults(rw http.ResponseWriter, values map[string]any, err error) {
 	response := make(map[string]any)
 	code := http.StatusOK
 	if err != nil {
 		response["error"] = err.Error()
 		code = http.StatusBadRequest
 	} else {
-		response[path] = val
+		maps.Copy(response, values)
 	}
 
 	body, err := json.Marshal(response)
@@ -148,6 +268,106 @@ func (d *Datasource) handleResourceSpreadsheets(rw http.ResponseWriter, req *htt
 		return
 	}
 
-	res, err := d.googlesheets.GetSpreadsheets(ctx, *config)
-	writeResult(rw, "spreadsheets", res, err)
+	params := req.URL.Query()
+	res, nextPageToken, err := d.googlesheets.GetSpreadsheets(ctx, *config, params.Get("search"), params.Get("pageToken"))
+	writeResults(rw, map[string]any{"spreadsheets": res, "nextPageToken": nextPageToken}, err)
+}
+
+func (d *Datasource) handleResourceSheets(rw http.ResponseWriter, req *http.Request) {
+	log.DefaultLogger.Debug("Received resource call", "url", req.URL.String())
+	if req.Method != http.MethodGet {
+		return
+	}
+
+	ctx := req.Context()
+	config, err := models.LoadSettings(backend.PluginConfigFromContext(ctx))
+	if err != nil {
+		writeResult(rw, "?", nil, err)
+		return
+	}
+
+	spreadsheetID := req.URL.Query().Get("spreadsheet")
+	if spreadsheetID == "" {
+		writeResult(rw, "sheets", nil, errors.New("missing spreadsheet parameter"))
+		return
+	}
+
+	res, err := d.googlesheets.GetSheets(ctx, *config, spreadsheetID)
+	writeResult(rw, "sheets", res, err)
+}
+
+func (d *Datasource) handleResourceRanges(rw http.ResponseWriter, req *http.Request) {
+	log.DefaultLogger.Debug("Received resource call", "url", req.URL.String())
+	if req.Method != http.MethodGet {
+		return
+	}
+
+	ctx := req.Context()
+	config, err := models.LoadSettings(backend.PluginConfigFromContext(ctx))
+	if err != nil {
+		writeResult(rw, "?", nil, err)
+		return
+	}
+
+	spreadsheetID := req.URL.Query().Get("spreadsheet")
+	if spreadsheetID == "" {
+		writeResult(rw, "ranges", nil, errors.New("missing spreadsheet parameter"))
+		return
+	}
+
+	res, err := d.googlesheets.GetRanges(ctx, *config, spreadsheetID)
+	writeResult(rw, "ranges", res, err)
+}
+
+func (d *Datasource) handleResourceSchema(rw http.ResponseWriter, req *http.Request) {
+	log.DefaultLogger.Debug("Received resource call", "url", req.URL.String())
+	if req.Method != http.MethodGet {
+		return
+	}
+
+	ctx := req.Context()
+	config, err := models.LoadSettings(backend.PluginConfigFromContext(ctx))
+	if err != nil {
+		writeResult(rw, "?", nil, err)
+		return
+	}
+
+	qm, err := getSchemaQueryModel(req)
+	if err != nil {
+		writeResult(rw, "schema", nil, err)
+		return
+	}
+
+	res, err := d.googlesheets.GetSchema(ctx, *config, qm)
+	writeResult(rw, "schema", res, err)
+}
+
+// getSchemaQueryModel reads the range and the header options of a schema request.
+func getSchemaQueryModel(req *http.Request) (*models.QueryModel, error) {
+	params := req.URL.Query()
+	qm := &models.QueryModel{
+		Spreadsheet: params.Get("spreadsheet"),
+		Range:       params.Get("range"),
+	}
+	if qm.Spreadsheet == "" {
+		return nil, errors.New("missing spreadsheet parameter")
+	}
+
+	var err error
+	if value := params.Get("headerRowIndex"); value != "" {
+		if qm.HeaderRowIndex, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid headerRowIndex parameter: %w", err)
+		}
+	}
+	if value := params.Get("headerRowCount"); value != "" {
+		if qm.HeaderRowCount, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid headerRowCount parameter: %w", err)
+		}
+	}
+	if value := params.Get("noHeader"); value != "" {
+		if qm.NoHeader, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid noHeader parameter: %w", err)
+		}
+	}
+	return qm, nil
 }
@@ -0,0 +1,341 @@
+package googlesheets
+
+import (
+	"cmp"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+const (
+	// diskCacheDirName is the directory of the disk caches, within the user cache directory.
+	diskCacheDirName = "grafana-googlesheets-datasource"
+	// diskCachePruneInterval is how often the expired files of the disk caches are removed.
+	diskCachePruneInterval = 10 * time.Minute
+)
+
+// diskCache is a cache persisting the spreadsheet data on disk, so that it survives plugin restarts. The values
+// are kept in a memory cache too, which serves them until they are evicted or the plugin restarts. Only the
+// spreadsheet data is written to disk, the metadata of the spreadsheets being cheap to fetch again.
+type diskCache struct {
+	Cache
+	dir string
+	// gcm encrypts the data written to disk, if set
+	gcm cipher.AEAD
+	// omit keeps the data from being written to disk, when it must be encrypted without a key
+	omit bool
+	// stop ends the periodic pruning, if started
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// diskCacheEntry is the content of a cache file.
+type diskCacheEntry struct {
+	Expires time.Time `json:"expires"`
+	// Data is the JSON of the spreadsheet data, encrypted when the cache is
+	Data []byte `json:"data"`
+}
+
+// newDiskCache returns a disk cache in the given directory, in front of which is the memory cache. When encrypt
+// is set, the data is encrypted with the key, or omitted from disk without a key. Expired files are removed.
+func newDiskCache(memory Cache, dir string, encrypt bool, key string) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("could not create the cache directory: %w", err)
+	}
+
+	c := &diskCache{Cache: memory, dir: dir}
+	if encrypt {
+		if key == "" {
+			log.DefaultLogger.Warn("Missing disk cache encryption key, the spreadsheet data is not written to disk")
+			c.omit = true
+		} else {
+			// The key is hashed to the size of an AES-256 key
+			hash := sha256.Sum256([]byte(key))
+			block, err := aes.NewCipher(hash[:])
+			if err != nil {
+				return nil, err
+			}
+			if c.gcm, err = cipher.NewGCM(block); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c.prune()
+	return c, nil
+}
+
+// getDiskCacheRoot returns the directory holding the disk caches of all the datasources.
+func getDiskCacheRoot() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, diskCacheDirName)
+}
+
+// getDiskCacheDir returns the cache directory of the settings of a datasource. Each version of the settings gets
+// its own directory, so that the data fetched with other credentials or scopes is never read.
+func getDiskCacheDir(root string, settings backend.DataSourceInstanceSettings) string {
+	datasourceUID := cmp.Or(settings.UID, strconv.FormatInt(settings.ID, 10))
+	return filepath.Join(root, datasourceUID, getSettingsFingerprint(settings))
+}
+
+// getSettingsFingerprint returns a hash of the settings of a datasource, including its secrets.
+func getSettingsFingerprint(settings backend.DataSourceInstanceSettings) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%d\n%s\n%s\n", settings.Updated.UnixNano(), settings.URL, settings.JSONData)
+	for _, key := range slices.Sorted(maps.Keys(settings.DecryptedSecureJSONData)) {
+		_, _ = fmt.Fprintf(hash, "%q=%q\n", key, settings.DecryptedSecureJSONData[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// removeOtherDiskCacheDirs removes the cache directories of the previous settings of the datasource of a directory.
+func removeOtherDiskCacheDirs(dir string) {
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != filepath.Base(dir) {
+			_ = os.RemoveAll(filepath.Join(filepath.Dir(dir), entry.Name()))
+		}
+	}
+}
+
+func (c *diskCache) Get(key string) (any, bool) {
+	value, _, found := c.GetWithExpiration(key)
+	return value, found
+}
+
+// GetWithExpiration returns the value of the memory cache, else the spreadsheet data read from disk, which is
+// then kept in the memory cache until it expires.
+func (c *diskCache) GetWithExpiration(key string) (any, time.Time, bool) {
+	if value, expires, found := c.Cache.GetWithExpiration(key); found {
+		return value, expires, true
+	}
+	if c.omit {
+		return nil, time.Time{}, false
+	}
+
+	sheetData, expires, err := c.read(key)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.DefaultLogger.Warn("Could not read the disk cache", "error", err)
+		}
+		return nil, time.Time{}, false
+	}
+	c.Cache.Set(key, sheetData, time.Until(expires))
+	return sheetData, expires, true
+}
+
+// Set stores the value in the memory cache, and writes the spreadsheet data that expires to disk.
+func (c *diskCache) Set(key string, value any, d time.Duration) {
+	c.Cache.Set(key, value, d)
+
+	sheetData, ok := value.(*spreadsheetData)
+	if !ok || c.omit || d <= 0 {
+		return
+	}
+	if err := c.write(key, sheetData, d); err != nil {
+		log.DefaultLogger.Warn("Could not write the disk cache", "error", err)
+	}
+}
+
+// path returns the file of a key, whose name is hashed as keys hold ranges and sheet titles.
+func (c *diskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (c *diskCache) read(key string) (*spreadsheetData, time.Time, error) {
+	path := c.path(key)
+	entry, err := readDiskCacheEntry(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if time.Now().After(entry.Expires) {
+		_ = os.Remove(path)
+		return nil, time.Time{}, os.ErrNotExist
+	}
+
+	sheetData, err := c.decode(entry.Data)
+	if err != nil {
+		// The data was written with another encryption key or setting
+		_ = os.Remove(path)
+		return nil, time.Time{}, err
+	}
+	return sheetData, entry.Expires, nil
+}
+
+func (c *diskCache) decode(data []byte) (*spreadsheetData, error) {
+	if c.gcm != nil {
+		var err error
+		if data, err = c.decrypt(data); err != nil {
+			return nil, err
+		}
+	}
+	sheetData := &spreadsheetData{}
+	if err := json.Unmarshal(data, sheetData); err != nil {
+		return nil, err
+	}
+	return sheetData, nil
+}
+
+func (c *diskCache) write(key string, sheetData *spreadsheetData, d time.Duration) error {
+	dataJSON, err := json.Marshal(sheetData)
+	if err != nil {
+		return err
+	}
+	entry := diskCacheEntry{Expires: time.Now().Add(d), Data: dataJSON}
+	if c.gcm != nil {
+		if entry.Data, err = c.encrypt(dataJSON); err != nil {
+			return err
+		}
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// The directory may have been pruned while empty
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	// The file is renamed once written, so that readers never see a partial file
+	file, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	if _, err := file.Write(entryJSON); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.path(key))
+}
+
+// encrypt seals the data with a random nonce, which prefixes the result.
+func (c *diskCache) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *diskCache) decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted cache data")
+	}
+	nonce, sealed := ciphertext[:c.gcm.NonceSize()], ciphertext[c.gcm.NonceSize():]
+	return c.gcm.Open(nil, nonce, sealed, nil)
+}
+
+// startPruning removes the expired files of all the disk caches under the root at each interval, including
+// those of the deleted datasources, until the cache is closed.
+func (c *diskCache) startPruning(root string, interval time.Duration) {
+	c.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.prune()
+				pruneDiskCaches(root, c.dir)
+			}
+		}
+	}()
+}
+
+// close stops the pruning. The directory is kept, as an instance with the same settings may read it again, and
+// is removed by the instance replacing the settings.
+func (c *diskCache) close() {
+	c.stopOnce.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+		}
+	})
+}
+
+// prune removes the expired files, and all the files when the data must not be written to disk.
+func (c *diskCache) prune() {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		if !c.omit {
+			entry, err := readDiskCacheEntry(path)
+			if err == nil && time.Now().Before(entry.Expires) {
+				continue
+			}
+		}
+		_ = os.Remove(path)
+	}
+}
+
+// pruneDiskCaches removes the expired and unreadable files under the root, then the empty directories but the
+// directory of the cache pruning them. The caches of the other instances recreate their directory when writing.
+func pruneDiskCaches(root string, keep string) {
+	dirs := []string{}
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if path != root && path != keep && path != filepath.Dir(keep) {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		if diskEntry, err := readDiskCacheEntry(path); err != nil || time.Now().After(diskEntry.Expires) {
+			_ = os.Remove(path)
+		}
+		return nil
+	})
+
+	// The deepest directories are removed first, and removing a directory that isn't empty fails
+	slices.Reverse(dirs)
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+}
+
+func readDiskCacheEntry(path string) (*diskCacheEntry, error) {
+	entryJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &diskCacheEntry{}
+	if err := json.Unmarshal(entryJSON, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,184 @@
+package googlesheets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskCache(t *testing.T, dir string, encrypt bool, key string) *diskCache {
+	t.Helper()
+	c, err := newDiskCache(cache.New(300*time.Second, 50*time.Second), dir, encrypt, key)
+	require.NoError(t, err)
+	return c
+}
+
+func getDiskCacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	return paths
+}
+
+func TestDiskCache(t *testing.T) {
+	sheetData := &spreadsheetData{
+		Grids:    []*sheetGrid{{Title: "Sheet1", Data: newTestGrid([]string{"Region"}, []string{"North"})}},
+		TimeZone: "Europe/Berlin",
+	}
+
+	t.Run("spreadsheet data survives restarts", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestDiskCache(t, dir, false, "").Set("someIdA1:O", sheetData, time.Minute)
+
+		// The memory cache is empty after a restart
+		restarted := newTestDiskCache(t, dir, false, "")
+		value, expires, found := restarted.GetWithExpiration("someIdA1:O")
+		require.True(t, found)
+		assert.Equal(t, sheetData, value)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expires, time.Second)
+		assert.Equal(t, 1, restarted.ItemCount())
+	})
+
+	t.Run("expired data is removed", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestDiskCache(t, dir, false, "").Set("someIdA1:O", sheetData, time.Millisecond)
+		require.Len(t, getDiskCacheFiles(t, dir), 1)
+		time.Sleep(5 * time.Millisecond)
+
+		restarted := newTestDiskCache(t, dir, false, "")
+		_, found := restarted.Get("someIdA1:O")
+		assert.False(t, found)
+		assert.Empty(t, getDiskCacheFiles(t, dir))
+	})
+
+	t.Run("other values are only kept in memory", func(t *testing.T) {
+		dir := t.TempDir()
+		c := newTestDiskCache(t, dir, false, "")
+		c.Set("sheets:someId", []SheetInfo{{Title: "Sheet1"}}, time.Minute)
+
+		_, found := c.Get("sheets:someId")
+		assert.True(t, found)
+		assert.Empty(t, getDiskCacheFiles(t, dir))
+	})
+
+	t.Run("encrypted data is read with the same key only", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestDiskCache(t, dir, true, "secret").Set("someIdA1:O", sheetData, time.Minute)
+
+		files := getDiskCacheFiles(t, dir)
+		require.Len(t, files, 1)
+		content, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "North")
+		assert.NotContains(t, string(content), "Europe/Berlin")
+
+		value, found := newTestDiskCache(t, dir, true, "secret").Get("someIdA1:O")
+		require.True(t, found)
+		assert.Equal(t, sheetData, value)
+
+		_, found = newTestDiskCache(t, dir, true, "other secret").Get("someIdA1:O")
+		assert.False(t, found)
+		assert.Empty(t, getDiskCacheFiles(t, dir))
+	})
+
+	t.Run("data is omitted from disk when it must be encrypted without a key", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestDiskCache(t, dir, false, "").Set("someIdA1:O", sheetData, time.Minute)
+
+		c := newTestDiskCache(t, dir, true, "")
+		assert.Empty(t, getDiskCacheFiles(t, dir))
+		c.Set("someIdA1:O", sheetData, time.Minute)
+		assert.Empty(t, getDiskCacheFiles(t, dir))
+
+		_, found := c.Get("someIdA1:O")
+		assert.True(t, found)
+	})
+}
+
+func TestGetDiskCacheDir(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		UID:                     "someUid",
+		Updated:                 time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC),
+		JSONData:                []byte(`{"authenticationType":"jwt","diskCache":true}`),
+		DecryptedSecureJSONData: map[string]string{"jwt": "first"},
+	}
+	dir := getDiskCacheDir("root", settings)
+	assert.Equal(t, filepath.Join("root", "someUid"), filepath.Dir(dir))
+	assert.Equal(t, dir, getDiskCacheDir("root", settings))
+
+	// The credentials and the settings get their own directory
+	updated := settings
+	updated.Updated = settings.Updated.Add(time.Minute)
+	assert.NotEqual(t, dir, getDiskCacheDir("root", updated))
+	rotated := settings
+	rotated.DecryptedSecureJSONData = map[string]string{"jwt": "second"}
+	assert.NotEqual(t, dir, getDiskCacheDir("root", rotated))
+}
+
+func TestDiskCacheDirs(t *testing.T) {
+	sheetData := &spreadsheetData{Grids: []*sheetGrid{{Title: "Sheet1", Data: newTestGrid([]string{"Region"}, []string{"North"})}}}
+
+	t.Run("directories of previous settings are removed", func(t *testing.T) {
+		root := t.TempDir()
+		previous := filepath.Join(root, "someUid", "previous")
+		newTestDiskCache(t, previous, false, "").Set("someIdA1:O", sheetData, time.Minute)
+		other := filepath.Join(root, "otherUid", "other")
+		newTestDiskCache(t, other, false, "").Set("someIdA1:O", sheetData, time.Minute)
+
+		current := filepath.Join(root, "someUid", "current")
+		removeOtherDiskCacheDirs(current)
+		assert.NoDirExists(t, previous)
+		assert.Len(t, getDiskCacheFiles(t, other), 1)
+	})
+
+	t.Run("closed caches are kept", func(t *testing.T) {
+		root := t.TempDir()
+		dir := filepath.Join(root, "someUid", "current")
+		c := newTestDiskCache(t, dir, false, "")
+		c.startPruning(root, time.Hour)
+		c.Set("someIdA1:O", sheetData, time.Minute)
+
+		c.close()
+		c.close()
+		// An instance with the same settings, such as after an idle eviction, reads the data again
+		_, found := newTestDiskCache(t, dir, false, "").Get("someIdA1:O")
+		assert.True(t, found)
+	})
+
+	t.Run("expired files of all the datasources are pruned", func(t *testing.T) {
+		root := t.TempDir()
+		deleted := filepath.Join(root, "deletedUid", "settings")
+		newTestDiskCache(t, deleted, false, "").Set("someIdA1:O", sheetData, time.Millisecond)
+		current := filepath.Join(root, "someUid", "settings")
+		newTestDiskCache(t, current, false, "").Set("someIdA1:O", sheetData, time.Minute)
+		time.Sleep(5 * time.Millisecond)
+
+		pruneDiskCaches(root, current)
+		assert.NoDirExists(t, filepath.Join(root, "deletedUid"))
+		assert.Len(t, getDiskCacheFiles(t, current), 1)
+		assert.DirExists(t, root)
+	})
+
+	t.Run("caches are written after their directory is pruned", func(t *testing.T) {
+		root := t.TempDir()
+		current := filepath.Join(root, "someUid", "settings")
+		c := newTestDiskCache(t, current, false, "")
+		other := filepath.Join(root, "otherUid", "settings")
+		otherCache := newTestDiskCache(t, other, false, "")
+
+		pruneDiskCaches(root, current)
+		assert.DirExists(t, current)
+		assert.NoDirExists(t, other)
+
+		c.Set("someIdA1:O", sheetData, time.Minute)
+		otherCache.Set("someIdA1:O", sheetData, time.Minute)
+		assert.Len(t, getDiskCacheFiles(t, current), 1)
+		assert.Len(t, getDiskCacheFiles(t, other), 1)
+	})
+}
@@ -0,0 +1,196 @@
+package googlesheets
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/araddon/dateparse"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	filterOperatorEqual          = "="
+	filterOperatorNotEqual       = "!="
+	filterOperatorLess           = "<"
+	filterOperatorLessOrEqual    = "<="
+	filterOperatorGreater        = ">"
+	filterOperatorGreaterOrEqual = ">="
+	filterOperatorContains       = "contains"
+	filterOperatorRegex          = "regex"
+	filterOperatorIn             = "in"
+)
+
+// filterFrame returns the rows of the frame that match all the filters.
+// Null cells never match a filter, and times without timezone are read in the given location.
+func filterFrame(frame *data.Frame, filters []models.FilterModel, loc *time.Location) (*data.Frame, error) {
+	for _, filter := range filters {
+		field, fieldIndex := frame.FieldByName(filter.Column)
+		if fieldIndex < 0 {
+			return nil, backend.DownstreamError(fmt.Errorf("unknown filter column %q", filter.Column))
+		}
+
+		predicate, err := newFilterPredicate(field, filter, loc)
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid filter on column %q: %w", filter.Column, err))
+		}
+
+		frame, err = frame.FilterRowsByField(fieldIndex, predicate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return frame, nil
+}
+
+// newFilterPredicate returns a function matching the values of the field against the filter.
+func newFilterPredicate(field *data.Field, filter models.FilterModel, loc *time.Location) (func(any) (bool, error), error) {
+	operator := strings.ToLower(strings.TrimSpace(filter.Operator))
+	switch operator {
+	case filterOperatorContains:
+		return func(i any) (bool, error) {
+			value, ok := cellValue(i)
+			return ok && strings.Contains(formatCellValue(value), filter.Value), nil
+		}, nil
+	case filterOperatorRegex:
+		pattern, err := regexp.Compile(filter.Value)
+		if err != nil {
+			return nil, err
+		}
+		return func(i any) (bool, error) {
+			value, ok := cellValue(i)
+			return ok && pattern.MatchString(formatCellValue(value)), nil
+		}, nil
+	}
+
+	targets := []string{filter.Value}
+	if operator == filterOperatorIn {
+		targets = strings.Split(filter.Value, ",")
+		for i := range targets {
+			targets[i] = strings.TrimSpace(targets[i])
+		}
+	}
+
+	switch field.Type().NonNullableType() {
+	case data.FieldTypeFloat64:
+		return newComparePredicate(operator, targets, func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		}, cmp.Compare[float64])
+	case data.FieldTypeTime:
+		return newComparePredicate(operator, targets, func(s string) (time.Time, error) {
+			return dateparse.ParseIn(s, loc)
+		}, time.Time.Compare)
+	case data.FieldTypeBool:
+		return newComparePredicate(operator, targets, func(s string) (bool, error) {
+			return strconv.ParseBool(strings.ToLower(s))
+		}, compareBool)
+	default:
+		return newComparePredicate(operator, targets, func(s string) (string, error) {
+			return s, nil
+		}, strings.Compare)
+	}
+}
+
+// newComparePredicate returns a function comparing values to the parsed targets with the operator.
+func newComparePredicate[T any](operator string, targets []string, parse func(string) (T, error), compare func(a, b T) int) (func(any) (bool, error), error) {
+	values := make([]T, 0, len(targets))
+	for _, target := range targets {
+		value, err := parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", target)
+		}
+		values = append(values, value)
+	}
+
+	var match func(c int) bool
+	switch operator {
+	case filterOperatorEqual, filterOperatorIn:
+		match = func(c int) bool { return c == 0 }
+	case filterOperatorNotEqual:
+		match = func(c int) bool { return c != 0 }
+	case filterOperatorLess:
+		match = func(c int) bool { return c < 0 }
+	case filterOperatorLessOrEqual:
+		match = func(c int) bool { return c <= 0 }
+	case filterOperatorGreater:
+		match = func(c int) bool { return c > 0 }
+	case filterOperatorGreaterOrEqual:
+		match = func(c int) bool { return c >= 0 }
+	default:
+		return nil, fmt.Errorf("unknown operator %q", operator)
+	}
+
+	return func(i any) (bool, error) {
+		cell, ok := cellValue(i)
+		if !ok {
+			return false, nil
+		}
+		value, ok := cell.(T)
+		if !ok {
+			return false, fmt.Errorf("unexpected value type %T", cell)
+		}
+		// Only the in operator takes several values, matching any of them
+		for _, target := range values {
+			if match(compare(value, target)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+// compareBool compares booleans, false being less than true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// cellValue returns the value of a frame cell, dereferencing the values of nullable fields.
+// It returns false for null cells.
+func cellValue(i any) (any, bool) {
+	switch v := i.(type) {
+	case *float64:
+		return derefCellValue(v)
+	case *string:
+		return derefCellValue(v)
+	case *time.Time:
+		return derefCellValue(v)
+	case *bool:
+		return derefCellValue(v)
+	default:
+		return v, v != nil
+	}
+}
+
+func derefCellValue[T any](v *T) (any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	return *v, true
+}
+
+// formatCellValue returns the text of a non-null cell value, as matched by the contains and regex operators.
+func formatCellValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,81 @@
+package googlesheets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFilterFrame() *data.Frame {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	str := func(s string) *string { return &s }
+	num := func(f float64) *float64 { return &f }
+	boolean := func(b bool) *bool { return &b }
+
+	return data.NewFrame("test",
+		data.NewField("Date", nil, []*time.Time{day(1), day(2), day(3), day(4)}),
+		data.NewField("Region", nil, []*string{str("North"), str("South"), nil, str("North-East")}),
+		data.NewField("Amount", nil, []*float64{num(10), num(20), num(30), nil}),
+		data.NewField("Paid", nil, []*bool{boolean(true), boolean(false), boolean(true), boolean(false)}),
+	)
+}
+
+func TestFilterFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []models.FilterModel
+		expected []float64
+	}{
+		{name: "number equal", filters: []models.FilterModel{{Column: "Amount", Operator: "=", Value: "20"}}, expected: []float64{20}},
+		{name: "number not equal excludes nulls", filters: []models.FilterModel{{Column: "Amount", Operator: "!=", Value: "20"}}, expected: []float64{10, 30}},
+		{name: "number greater", filters: []models.FilterModel{{Column: "Amount", Operator: ">", Value: "10"}}, expected: []float64{20, 30}},
+		{name: "number in", filters: []models.FilterModel{{Column: "Amount", Operator: "in", Value: "10, 30"}}, expected: []float64{10, 30}},
+		{name: "string contains", filters: []models.FilterModel{{Column: "Region", Operator: "contains", Value: "North"}}, expected: []float64{10}},
+		{name: "string regex", filters: []models.FilterModel{{Column: "Region", Operator: "regex", Value: "^(North|South)$"}}, expected: []float64{10, 20}},
+		{name: "string in", filters: []models.FilterModel{{Column: "Region", Operator: "in", Value: "South,North"}}, expected: []float64{10, 20}},
+		{name: "time less or equal", filters: []models.FilterModel{{Column: "Date", Operator: "<=", Value: "2026-03-02"}}, expected: []float64{10, 20}},
+		{name: "boolean equal", filters: []models.FilterModel{{Column: "Paid", Operator: "=", Value: "TRUE"}}, expected: []float64{10, 30}},
+		{name: "filters are combined", filters: []models.FilterModel{
+			{Column: "Paid", Operator: "=", Value: "true"},
+			{Column: "Date", Operator: ">", Value: "2026-03-01"},
+		}, expected: []float64{30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame, err := filterFrame(newTestFilterFrame(), tt.filters, time.UTC)
+			require.NoError(t, err)
+
+			amounts := []float64{}
+			field, _ := frame.FieldByName("Amount")
+			for i := 0; i < field.Len(); i++ {
+				if v := field.At(i).(*float64); v != nil {
+					amounts = append(amounts, *v)
+				}
+			}
+			assert.Equal(t, tt.expected, amounts)
+		})
+	}
+
+	t.Run("invalid filters are downstream errors", func(t *testing.T) {
+		for _, filter := range []models.FilterModel{
+			{Column: "Unknown", Operator: "=", Value: "1"},
+			{Column: "Amount", Operator: "~", Value: "1"},
+			{Column: "Amount", Operator: "=", Value: "one"},
+			{Column: "Region", Operator: "regex", Value: "("},
+		} {
+			_, err := filterFrame(newTestFilterFrame(), []models.FilterModel{filter}, time.UTC)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		}
+	})
+}
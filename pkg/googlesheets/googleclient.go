@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/grafana/grafana-google-sdk-go/pkg/tokenprovider"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -11,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 
@@ -47,7 +49,9 @@ type GoogleClient struct {
 }
 
 type client interface {
-	GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRange string, includeGridData bool) (*sheets.Spreadsheet, error)
+	GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRanges []string, includeGridData bool) (*sheets.Spreadsheet, error)
+	GetValues(ctx context.Context, spreadSheetID string, sheetRanges []string) (*sheets.BatchGetValuesResponse, error)
+	GetFile(ctx context.Context, fileID string) (*drive.File, error)
 }
 
 // NewGoogleClient creates a new client and initializes a sheet service and a drive service
@@ -96,37 +100,100 @@ func (gc *GoogleClient) TestClient() error {
 	return nil
 }
 
-// GetSpreadsheet gets a google spreadsheet struct by id and range
-func (gc *GoogleClient) GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRange string, _ bool) (*sheets.Spreadsheet, error) {
+const (
+	// spreadsheetGridFields are the fields of the spreadsheet read from its grid data
+	spreadsheetGridFields = "properties/timeZone,namedRanges," +
+		"sheets(properties(sheetId,title),data(startRow,startColumn,rowData/values(" +
+		"formattedValue,effectiveValue,effectiveFormat/numberFormat,userEnteredFormat/numberFormat)))"
+	// spreadsheetMetadataFields are the fields of the spreadsheet read without its grid data
+	spreadsheetMetadataFields = "properties/timeZone,namedRanges,sheets(properties,protectedRanges)"
+)
+
+// GetSpreadsheet gets a google spreadsheet struct by id and ranges. Only the fields read by the datasource are fetched.
+func (gc *GoogleClient) GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRanges []string, includeGridData bool) (*sheets.Spreadsheet, error) {
 	req := gc.sheetsService.Spreadsheets.Get(spreadSheetID)
-	if len(sheetRange) > 0 {
-		req = req.Ranges(sheetRange)
+	if len(sheetRanges) > 0 {
+		req = req.Ranges(sheetRanges...)
 	}
-	return req.IncludeGridData(true).Context(ctx).Do()
+	fields := spreadsheetMetadataFields
+	if includeGridData {
+		fields = spreadsheetGridFields
+	}
+	return req.IncludeGridData(includeGridData).Fields(googleapi.Field(fields)).Context(ctx).Do()
 }
 
-// GetSpreadsheetFiles lists all files with spreadsheet mimetype that the client has access to.
-func (gc *GoogleClient) GetSpreadsheetFiles() ([]*drive.File, error) {
-	fs := []*drive.File{}
-	pageToken := ""
-	for {
-		q := gc.driveService.Files.List().Q("mimeType='application/vnd.google-apps.spreadsheet'")
-		if pageToken != "" {
-			q = q.PageToken(pageToken)
-		}
-		r, err := q.Do()
-		if err != nil {
-			return nil, fmt.Errorf("failed to list spreadsheet files, page token %q: %w", pageToken, err)
-		}
+// GetValues gets the unformatted values of the ranges of a google spreadsheet, with times as serial numbers.
+func (gc *GoogleClient) GetValues(ctx context.Context, spreadSheetID string, sheetRanges []string) (*sheets.BatchGetValuesResponse, error) {
+	return gc.sheetsService.Spreadsheets.Values.BatchGet(spreadSheetID).
+		Ranges(sheetRanges...).
+		MajorDimension("ROWS").
+		ValueRenderOption("UNFORMATTED_VALUE").
+		DateTimeRenderOption("SERIAL_NUMBER").
+		Context(ctx).
+		Do()
+}
 
-		fs = append(fs, r.Files...)
-		pageToken = r.NextPageToken
-		if pageToken == "" {
-			break
-		}
+// SpreadsheetFilesQuery selects the spreadsheet files listed by GetSpreadsheetFiles.
+type SpreadsheetFilesQuery struct {
+	// Search keeps the files whose name contains the search term
+	Search string
+	// PageToken is the token of the page to list, as returned with the previous page
+	PageToken string
+	// RootFolderID keeps the files of a folder
+	RootFolderID string
+	// IncludeSharedDrives lists the files of shared drives too
+	IncludeSharedDrives bool
+}
+
+// spreadsheetFilesFields are the fields of the files listed by GetSpreadsheetFiles.
+const spreadsheetFilesFields = "nextPageToken,files(id,name,modifiedTime,owners(displayName,emailAddress),parents,webViewLink)"
+
+// spreadsheetFilesPageSize is the number of files listed per page, the maximum allowed by the Drive API.
+const spreadsheetFilesPageSize = 1000
+
+// GetSpreadsheetFiles lists a page of the files with spreadsheet mimetype that the client has access to,
+// most recently modified first. Trashed files are not listed.
+func (gc *GoogleClient) GetSpreadsheetFiles(ctx context.Context, query SpreadsheetFilesQuery) (*drive.FileList, error) {
+	q := gc.driveService.Files.List().
+		Q(getSpreadsheetFilesQuery(query)).
+		Fields(spreadsheetFilesFields).
+		OrderBy("modifiedTime desc,name").
+		PageSize(spreadsheetFilesPageSize).
+		SupportsAllDrives(true)
+	if query.IncludeSharedDrives {
+		q = q.IncludeItemsFromAllDrives(true).Corpora("allDrives")
 	}
+	if query.PageToken != "" {
+		q = q.PageToken(query.PageToken)
+	}
+
+	r, err := q.Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spreadsheet files, page token %q: %w", query.PageToken, err)
+	}
+	return r, nil
+}
+
+// getSpreadsheetFilesQuery returns the Drive search query of the spreadsheet files.
+func getSpreadsheetFilesQuery(query SpreadsheetFilesQuery) string {
+	escape := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	q := "mimeType='application/vnd.google-apps.spreadsheet' and trashed=false"
+	if query.Search != "" {
+		q += " and name contains '" + escape.Replace(query.Search) + "'"
+	}
+	if query.RootFolderID != "" {
+		q += " and '" + escape.Replace(query.RootFolderID) + "' in parents"
+	}
+	return q
+}
+
+// fileInfoFields are the fields of the Drive file metadata returned by GetFile.
+const fileInfoFields = "id,name,mimeType,createdTime,modifiedTime,owners(displayName,emailAddress),size,webViewLink," +
+	"version,lastModifyingUser(displayName,emailAddress),parents"
 
-	return fs, nil
+// GetFile gets the Drive metadata of a file or a folder by id.
+func (gc *GoogleClient) GetFile(ctx context.Context, fileID string) (*drive.File, error) {
+	return gc.driveService.Files.Get(fileID).Fields(fileInfoFields).SupportsAllDrives(true).Context(ctx).Do()
 }
 
 func createSheetsService(ctx context.Context, settings models.DatasourceSettings) (*sheets.Service, error) {
@@ -0,0 +1,44 @@
+package googlesheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestGetSpreadsheetFilesQuery(t *testing.T) {
+	t.Run("trashed files are excluded", func(t *testing.T) {
+		assert.Equal(t, "mimeType='application/vnd.google-apps.spreadsheet' and trashed=false", getSpreadsheetFilesQuery(SpreadsheetFilesQuery{}))
+	})
+
+	t.Run("search and folder are escaped", func(t *testing.T) {
+		q := getSpreadsheetFilesQuery(SpreadsheetFilesQuery{Search: `Bob's \ budget`, RootFolderID: "folder-id"})
+		assert.Equal(t, `mimeType='application/vnd.google-apps.spreadsheet' and trashed=false and name contains 'Bob\'s \\ budget' and 'folder-id' in parents`, q)
+	})
+}
+
+func TestGetSpreadsheetFields(t *testing.T) {
+	var fields []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		_, _ = w.Write([]byte(`{"spreadsheetId":"someId"}`))
+	}))
+	defer server.Close()
+
+	sheetsService, err := sheets.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	gc := &GoogleClient{sheetsService: sheetsService}
+
+	_, err = gc.GetSpreadsheet(context.Background(), "someId", []string{"A1:B2"}, true)
+	require.NoError(t, err)
+	_, err = gc.GetSpreadsheet(context.Background(), "someId", nil, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{spreadsheetGridFields, spreadsheetMetadataFields}, fields)
+}
@@ -1,10 +1,15 @@
 package googlesheets
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"net"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,18 +18,24 @@ import (
 	"github.com/araddon/dateparse"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
-	"github.com/patrickmn/go-cache"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/sheets/v4"
 )
 
 // GoogleSheets provides an interface to the Google Sheets API.
 type GoogleSheets struct {
-	Cache *cache.Cache
+	Cache Cache
+	// fetches coalesces the concurrent fetches of the same data. There is a GoogleSheets per datasource instance,
+	// so the fetches sharing a cache key also share the credentials.
+	fetches singleflight.Group
 }
 
-// Query queries a spreadsheet and returns a corresponding data frame.
+// Query queries a spreadsheet and returns a data frame for each of the queried ranges,
+// or a single data frame when the sheets are stacked together.
 func (gs *GoogleSheets) Query(ctx context.Context, refID string, qm *models.QueryModel, config models.DatasourceSettings, timeRange backend.TimeRange) (dr backend.DataResponse) {
 	client, err := NewGoogleClient(ctx, config)
 	if err != nil {
@@ -33,6 +44,11 @@ func (gs *GoogleSheets) Query(ctx context.Context, refID string, qm *models.Quer
 		return
 	}
 
+	return gs.query(ctx, client, refID, qm, timeRange)
+}
+
+// query queries a spreadsheet with the client.
+func (gs *GoogleSheets) query(ctx context.Context, client client, refID string, qm *models.QueryModel, timeRange backend.TimeRange) (dr backend.DataResponse) {
 	// This result may be cached
 	sheetData, meta, err := gs.getSheetData(ctx, client, qm)
 	if err != nil {
@@ -40,138 +56,867 @@ func (gs *GoogleSheets) Query(ctx context.Context, refID string, qm *models.Quer
 		return
 	}
 
-	frame, err := gs.transformSheetToDataFrame(ctx, sheetData, meta, refID, qm)
+	loc, err := getLocation(ctx, qm, sheetData.TimeZone)
 	if err != nil {
-		dr.Error = err
+		dr = backend.ErrorResponseWithErrorSource(err)
 		return
 	}
-	if frame == nil {
+	meta["timezone"] = loc.String()
+
+	var file *drive.File
+	if qm.Freshness || qm.FreshnessFrame {
+		file, err = client.GetFile(ctx, qm.Spreadsheet)
+		if err != nil {
+			dr = backend.ErrorResponseWithErrorSource(handleGoogleAPIError(ctx, err))
+			return
+		}
+		maps.Copy(meta, getFreshnessMeta(file))
+	}
+
+	var frames []*data.Frame
+	if qm.UnionSheets != "" {
+		var frame *data.Frame
+		frame, err = gs.transformUnionToDataFrame(ctx, sheetData, loc, meta, refID, qm)
+		frames = []*data.Frame{frame}
+	} else {
+		frames, err = gs.transformSheetsToDataFrames(ctx, sheetData, loc, meta, refID, qm)
+	}
+	if err != nil {
+		dr = backend.ErrorResponseWithErrorSource(err)
 		return
 	}
-	if qm.UseTimeFilter {
-		timeIndex := findTimeField(frame)
-		if timeIndex >= 0 {
-			frame, dr.Error = frame.FilterRowsByField(timeIndex, func(i any) (bool, error) {
-				val, ok := i.(*time.Time)
-				if !ok {
-					return false, fmt.Errorf("invalid time column: %#v", i)
-				}
-				if val == nil || val.Before(timeRange.From) || val.After(timeRange.To) {
-					return false, nil
-				}
-				return true, nil
-			})
+	for _, frame := range frames {
+		if qm.UseTimeFilter {
+			frame, err = filterFrameByTimeRange(frame, timeRange)
+			if err != nil {
+				dr.Error = err
+				return
+			}
 		}
+		if len(qm.Filters) > 0 {
+			frame, err = filterFrame(frame, qm.Filters, loc)
+			if err != nil {
+				dr = backend.ErrorResponseWithErrorSource(err)
+				return
+			}
+		}
+		if len(qm.GroupBy) > 0 || len(qm.Aggregations) > 0 {
+			frame, err = aggregateQueryFrame(frame, qm, loc)
+			if err != nil {
+				dr = backend.ErrorResponseWithErrorSource(err)
+				return
+			}
+		}
+		if qm.Downsample {
+			frame, err = downsampleFrame(frame, qm, loc)
+			if err != nil {
+				dr = backend.ErrorResponseWithErrorSource(err)
+				return
+			}
+		}
+		dr.Frames = append(dr.Frames, frame)
+	}
+	if qm.FreshnessFrame {
+		frame := newFreshnessFrame(file, time.Now())
+		frame.RefID = refID
+		dr.Frames = append(dr.Frames, frame)
 	}
-	dr.Frames = append(dr.Frames, frame)
 	return
 }
 
-// GetSpreadsheets gets spreadsheets from the Google API.
-func (gs *GoogleSheets) GetSpreadsheets(ctx context.Context, config models.DatasourceSettings) (map[string]string, error) {
+// getLocation returns the location in which the times of a query are read: the timezone set in the query,
+// else the timezone of the spreadsheet, else UTC.
+func getLocation(ctx context.Context, qm *models.QueryModel, spreadsheetTimeZone string) (*time.Location, error) {
+	if qm.Timezone != "" {
+		loc, err := time.LoadLocation(qm.Timezone)
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid timezone %q: %w", qm.Timezone, err))
+		}
+		return loc, nil
+	}
+
+	if spreadsheetTimeZone != "" {
+		loc, err := time.LoadLocation(spreadsheetTimeZone)
+		if err == nil {
+			return loc, nil
+		}
+		backend.Logger.FromContext(ctx).Warn("could not load timezone from spreadsheet", "timezone", spreadsheetTimeZone, "error", err)
+	}
+	return time.UTC, nil
+}
+
+// filterFrameByTimeRange drops the rows whose first time field is outside the time range.
+func filterFrameByTimeRange(frame *data.Frame, timeRange backend.TimeRange) (*data.Frame, error) {
+	timeIndex := findTimeField(frame)
+	if timeIndex < 0 {
+		return frame, nil
+	}
+	return frame.FilterRowsByField(timeIndex, func(i any) (bool, error) {
+		val, ok := i.(*time.Time)
+		if !ok {
+			return false, fmt.Errorf("invalid time column: %#v", i)
+		}
+		if val == nil || val.Before(timeRange.From) || val.After(timeRange.To) {
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// SpreadsheetInfo describes a spreadsheet file.
+type SpreadsheetInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Owner        string `json:"owner"`
+	ModifiedTime string `json:"modifiedTime"`
+	// ParentPath is the path of the folder of the spreadsheet, such as "My Drive/Reports"
+	ParentPath  string `json:"parentPath"`
+	WebViewLink string `json:"webViewLink"`
+}
+
+// GetSpreadsheets gets a page of spreadsheets from the Google API, whose name contains the search term if any,
+// most recently modified first. It returns the token of the next page, which is empty for the last page.
+func (gs *GoogleSheets) GetSpreadsheets(ctx context.Context, config models.DatasourceSettings, search string, pageToken string) ([]SpreadsheetInfo, string, error) {
+	client, err := NewGoogleClient(ctx, config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create Google API client: %w", err)
+	}
+
+	fileList, err := client.GetSpreadsheetFiles(ctx, newSpreadsheetFilesQuery(config, search, pageToken))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return gs.getSpreadsheetInfos(ctx, client, fileList.Files), fileList.NextPageToken, nil
+}
+
+// getSpreadsheetInfos describes the spreadsheet files, most recently modified first, then by name and id.
+func (gs *GoogleSheets) getSpreadsheetInfos(ctx context.Context, client client, files []*drive.File) []SpreadsheetInfo {
+	folderPaths := gs.getFolderPaths(ctx, client, files)
+	spreadsheetInfos := make([]SpreadsheetInfo, 0, len(files))
+	for _, file := range files {
+		info := SpreadsheetInfo{
+			ID:           file.Id,
+			Name:         file.Name,
+			ModifiedTime: file.ModifiedTime,
+			WebViewLink:  file.WebViewLink,
+		}
+		if len(file.Owners) > 0 {
+			info.Owner = getDriveUserName(file.Owners[0])
+		}
+		if len(file.Parents) > 0 {
+			info.ParentPath = folderPaths[file.Parents[0]]
+		}
+		spreadsheetInfos = append(spreadsheetInfos, info)
+	}
+
+	slices.SortStableFunc(spreadsheetInfos, func(a, b SpreadsheetInfo) int {
+		return cmp.Or(
+			compareDriveTimes(b.ModifiedTime, a.ModifiedTime),
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.ID, b.ID),
+		)
+	})
+	return spreadsheetInfos
+}
+
+// compareDriveTimes compares two times returned by the Drive API, missing times being the oldest.
+func compareDriveTimes(a, b string) int {
+	ta, tb := parseDriveTime(a), parseDriveTime(b)
+	switch {
+	case ta == nil && tb == nil:
+		return 0
+	case ta == nil:
+		return -1
+	case tb == nil:
+		return 1
+	default:
+		return ta.Compare(*tb)
+	}
+}
+
+const (
+	// maxFolderDepth is the maximum number of folders resolved in a folder path.
+	maxFolderDepth = 10
+	// maxFolderLookups is the maximum number of folder paths resolved at once.
+	maxFolderLookups = 8
+	// folderCacheDuration is how long the folders are cached, as their names and parents rarely change.
+	folderCacheDuration = time.Hour
+)
+
+// getFolderPaths returns the paths of the parent folders of the files by folder id. Each folder is resolved once,
+// the folders of different paths in parallel.
+func (gs *GoogleSheets) getFolderPaths(ctx context.Context, client client, files []*drive.File) map[string]string {
+	folderIDs := []string{}
+	for _, file := range files {
+		if len(file.Parents) > 0 && !slices.Contains(folderIDs, file.Parents[0]) {
+			folderIDs = append(folderIDs, file.Parents[0])
+		}
+	}
+
+	paths := make([]string, len(folderIDs))
+	var group errgroup.Group
+	group.SetLimit(maxFolderLookups)
+	for i, folderID := range folderIDs {
+		group.Go(func() error {
+			paths[i] = gs.getFolderPath(ctx, client, folderID)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	folderPaths := make(map[string]string, len(folderIDs))
+	for i, folderID := range folderIDs {
+		folderPaths[folderID] = paths[i]
+	}
+	return folderPaths
+}
+
+// getFolderPath returns the path of a Drive folder, from the root of its drive. The folders are cached,
+// and a folder that can't be read, such as a folder shared with the client but not its parent, ends the path.
+func (gs *GoogleSheets) getFolderPath(ctx context.Context, client client, folderID string) string {
+	names := []string{}
+	for range maxFolderDepth {
+		file := gs.getFolder(ctx, client, folderID)
+		if file == nil {
+			break
+		}
+		names = append(names, file.Name)
+		if len(file.Parents) == 0 {
+			break
+		}
+		folderID = file.Parents[0]
+	}
+
+	slices.Reverse(names)
+	return strings.Join(names, "/")
+}
+
+// getFolder returns a cached Drive folder, or nil if it can't be read. The folders that can't be read are cached
+// too, and the concurrent lookups of a folder, such as a parent shared by several paths, are made once.
+func (gs *GoogleSheets) getFolder(ctx context.Context, client client, folderID string) *drive.File {
+	cacheKey := "folder:" + folderID
+	folder, ok := gs.Cache.Get(cacheKey)
+	if !ok {
+		folder, _, _ = gs.fetches.Do(cacheKey, func() (any, error) {
+			// The folder may have been cached by a lookup that ended meanwhile
+			if folder, ok := gs.Cache.Get(cacheKey); ok {
+				return folder, nil
+			}
+			file, err := client.GetFile(ctx, folderID)
+			if err != nil {
+				backend.Logger.FromContext(ctx).Debug("could not get folder", "folderId", folderID, "error", err)
+				// The empty id marks the folders that can't be read, which may be shared with the client later
+				gs.Cache.Set(cacheKey, "", sheetsCacheDuration)
+				return nil, nil
+			}
+			gs.Cache.Set(cacheKey, file, folderCacheDuration)
+			return file, nil
+		})
+	}
+
+	file, _ := folder.(*drive.File)
+	return file
+}
+
+// newSpreadsheetFilesQuery returns the query of the spreadsheet files scoped by the datasource settings.
+func newSpreadsheetFilesQuery(config models.DatasourceSettings, search string, pageToken string) SpreadsheetFilesQuery {
+	return SpreadsheetFilesQuery{
+		Search:              search,
+		PageToken:           pageToken,
+		RootFolderID:        config.RootFolderID,
+		IncludeSharedDrives: config.IncludeSharedDrives,
+	}
+}
+
+// sheetsCacheDuration is how long the sheets of a spreadsheet are cached.
+const sheetsCacheDuration = time.Minute
+
+// SheetInfo describes a sheet of a spreadsheet.
+type SheetInfo struct {
+	Title       string `json:"title"`
+	SheetID     int64  `json:"sheetId"`
+	Index       int64  `json:"index"`
+	Hidden      bool   `json:"hidden"`
+	RowCount    int64  `json:"rowCount"`
+	ColumnCount int64  `json:"columnCount"`
+}
+
+// GetSheets gets the sheets of a spreadsheet from the Google API.
+func (gs *GoogleSheets) GetSheets(ctx context.Context, config models.DatasourceSettings, spreadsheetID string) ([]SheetInfo, error) {
 	client, err := NewGoogleClient(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Google API client: %w", err)
 	}
 
-	files, err := client.GetSpreadsheetFiles()
+	return gs.getSheets(ctx, client, spreadsheetID)
+}
+
+// getSheets gets the sheets of a spreadsheet, without their grid data. The result is cached.
+func (gs *GoogleSheets) getSheets(ctx context.Context, client client, spreadsheetID string) ([]SheetInfo, error) {
+	cacheKey := "sheets:" + spreadsheetID
+	if item, found := gs.Cache.Get(cacheKey); found {
+		if sheetInfos, ok := item.([]SheetInfo); ok {
+			return sheetInfos, nil
+		}
+	}
+
+	result, err := client.GetSpreadsheet(ctx, spreadsheetID, nil, false)
 	if err != nil {
-		return nil, err
+		return nil, handleGoogleAPIError(ctx, err)
 	}
 
-	fileNames := make(map[string]string, len(files))
-	for _, i := range files {
-		fileNames[i.Id] = i.Name
+	sheetInfos := make([]SheetInfo, 0, len(result.Sheets))
+	for _, sheet := range result.Sheets {
+		if sheet.Properties == nil {
+			continue
+		}
+		info := SheetInfo{
+			Title:   sheet.Properties.Title,
+			SheetID: sheet.Properties.SheetId,
+			Index:   sheet.Properties.Index,
+			Hidden:  sheet.Properties.Hidden,
+		}
+		if sheet.Properties.GridProperties != nil {
+			info.RowCount = sheet.Properties.GridProperties.RowCount
+			info.ColumnCount = sheet.Properties.GridProperties.ColumnCount
+		}
+		sheetInfos = append(sheetInfos, info)
 	}
 
-	return fileNames, nil
+	gs.Cache.Set(cacheKey, sheetInfos, sheetsCacheDuration)
+	return sheetInfos, nil
 }
 
-// getSheetData gets grid data corresponding to a spreadsheet.
-func (gs *GoogleSheets) getSheetData(ctx context.Context, client client, qm *models.QueryModel) (*sheets.GridData, map[string]any, error) {
-	logger := backend.Logger.FromContext(ctx)
-	cacheKey := qm.Spreadsheet + qm.Range
-	if item, expires, found := gs.Cache.GetWithExpiration(cacheKey); found && qm.CacheDurationSeconds > 0 {
-		if gridData, ok := item.(*sheets.GridData); ok {
-			return gridData, map[string]any{
-				"hit":     true,
-				"expires": expires.Unix(),
-			}, nil
+// NamedRange is a named range of a spreadsheet, with its A1 range.
+type NamedRange struct {
+	Name         string `json:"name"`
+	NamedRangeID string `json:"namedRangeId"`
+	Range        string `json:"range"`
+}
+
+// ProtectedRange is a protected range of a spreadsheet, with its A1 range.
+type ProtectedRange struct {
+	ProtectedRangeID int64  `json:"protectedRangeId"`
+	Description      string `json:"description"`
+	Range            string `json:"range"`
+	NamedRangeID     string `json:"namedRangeId,omitempty"`
+	WarningOnly      bool   `json:"warningOnly"`
+}
+
+// RangesInfo holds the named and protected ranges of a spreadsheet.
+type RangesInfo struct {
+	NamedRanges     []NamedRange     `json:"namedRanges"`
+	ProtectedRanges []ProtectedRange `json:"protectedRanges"`
+}
+
+// GetRanges gets the named and protected ranges of a spreadsheet from the Google API.
+func (gs *GoogleSheets) GetRanges(ctx context.Context, config models.DatasourceSettings, spreadsheetID string) (*RangesInfo, error) {
+	client, err := NewGoogleClient(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google API client: %w", err)
+	}
+
+	return gs.getRanges(ctx, client, spreadsheetID)
+}
+
+// getRanges gets the named and protected ranges of a spreadsheet, with their A1 ranges. The result is cached.
+func (gs *GoogleSheets) getRanges(ctx context.Context, client client, spreadsheetID string) (*RangesInfo, error) {
+	cacheKey := "ranges:" + spreadsheetID
+	if item, found := gs.Cache.Get(cacheKey); found {
+		if rangesInfo, ok := item.(*RangesInfo); ok {
+			return rangesInfo, nil
 		}
-		return nil, nil, errors.New("invalid cache item not type of *sheets.GridData")
 	}
-	result, err := client.GetSpreadsheet(ctx, qm.Spreadsheet, qm.Range, true)
+
+	result, err := client.GetSpreadsheet(ctx, spreadsheetID, nil, false)
 	if err != nil {
-		if apiErr, ok := err.(*googleapi.Error); ok {
-			// Handle API-specific errors
-			// We use ErrorSourceFromHTTPStatus to determine error source based on HTTP status code
-			if apiErr.Code == 404 {
-				errWithSource := backend.DownstreamError(errors.New("spreadsheet not found"))
-				return nil, nil, errWithSource
+		return nil, handleGoogleAPIError(ctx, err)
+	}
+
+	titles := getSheetTitles(result)
+	rangesInfo := &RangesInfo{
+		NamedRanges:     make([]NamedRange, 0, len(result.NamedRanges)),
+		ProtectedRanges: []ProtectedRange{},
+	}
+	for _, namedRange := range result.NamedRanges {
+		if namedRange.Range == nil {
+			continue
+		}
+		rangesInfo.NamedRanges = append(rangesInfo.NamedRanges, NamedRange{
+			Name:         namedRange.Name,
+			NamedRangeID: namedRange.NamedRangeId,
+			Range:        getA1Range(titles[namedRange.Range.SheetId], namedRange.Range),
+		})
+	}
+	for _, sheet := range result.Sheets {
+		for _, protectedRange := range sheet.ProtectedRanges {
+			info := ProtectedRange{
+				ProtectedRangeID: protectedRange.ProtectedRangeId,
+				Description:      protectedRange.Description,
+				NamedRangeID:     protectedRange.NamedRangeId,
+				WarningOnly:      protectedRange.WarningOnly,
 			}
-			if apiErr.Message != "" {
-				logger.Warn("Google API Error: " + apiErr.Message)
-				err := fmt.Errorf("google API Error %d", apiErr.Code)
-				if backend.ErrorSourceFromHTTPStatus(apiErr.Code) == backend.ErrorSourceDownstream {
-					return nil, nil, backend.DownstreamError(err)
-				}
-				return nil, nil, err
+			if protectedRange.Range != nil {
+				info.Range = getA1Range(titles[protectedRange.Range.SheetId], protectedRange.Range)
 			}
+			rangesInfo.ProtectedRanges = append(rangesInfo.ProtectedRanges, info)
+		}
+	}
 
-			err := errors.New("unknown API error")
-			if backend.ErrorSourceFromHTTPStatus(apiErr.Code) == backend.ErrorSourceDownstream {
-				return nil, nil, backend.DownstreamError(err)
+	gs.Cache.Set(cacheKey, rangesInfo, sheetsCacheDuration)
+	return rangesInfo, nil
+}
+
+// getSheetTitles returns the titles of the sheets of a spreadsheet by sheet ID.
+func getSheetTitles(spreadsheet *sheets.Spreadsheet) map[int64]string {
+	titles := make(map[int64]string, len(spreadsheet.Sheets))
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties != nil {
+			titles[sheet.Properties.SheetId] = sheet.Properties.Title
+		}
+	}
+	return titles
+}
+
+// sheetGrid is the grid data returned for a single range, along with the title of its sheet.
+type sheetGrid struct {
+	Title string
+	Data  *sheets.GridData
+	// NamedRange is the name of the named range the grid data was queried with, if any
+	NamedRange string
+	// ResolvedRange is the A1 range of the named range
+	ResolvedRange string
+}
+
+// spreadsheetData is the data fetched for a query. This is what gets cached.
+type spreadsheetData struct {
+	Grids []*sheetGrid
+	// TimeZone is the timezone of the spreadsheet, such as "Europe/Berlin"
+	TimeZone string
+	// ModifiedTime is the Drive modified time of the spreadsheet before it was fetched, in the modifiedTime cache mode
+	ModifiedTime string
+	// FetchedAt is when the data was fetched, which tells if it is stale in the staleWhileRevalidate cache mode
+	FetchedAt time.Time
+}
+
+const (
+	// defaultMaxStale is how long after expiry the cached data may be served in the staleWhileRevalidate cache mode.
+	defaultMaxStale = 5 * time.Minute
+	// fetchTimeout bounds the shared fetches, which outlive the queries waiting for them, and the background
+	// refreshes of stale data, which no query waits for.
+	fetchTimeout = time.Minute
+)
+
+// getCacheDurations returns how long the data of a query is fresh, and how long it is cached. Data cached in the
+// staleWhileRevalidate mode outlives its freshness by the staleness window of the query.
+func getCacheDurations(qm *models.QueryModel) (fresh time.Duration, cached time.Duration) {
+	fresh = time.Duration(qm.CacheDurationSeconds) * time.Second
+	if qm.CacheMode != models.CacheModeStaleWhileRevalidate {
+		return fresh, fresh
+	}
+	if qm.MaxStaleSeconds > 0 {
+		return fresh, fresh + time.Duration(qm.MaxStaleSeconds)*time.Second
+	}
+	return fresh, fresh + defaultMaxStale
+}
+
+// getSheetDataCacheKey returns the cache key of the data of a query. The ranges are quoted, as sheet titles
+// may hold any character.
+func getSheetDataCacheKey(qm *models.QueryModel, ranges []string) string {
+	var key strings.Builder
+	key.WriteString(qm.Spreadsheet)
+	for _, r := range ranges {
+		key.WriteString("|")
+		key.WriteString(strconv.Quote(r))
+	}
+	if qm.UnionSheets != "" {
+		key.WriteString("|union:" + qm.UnionSheets)
+	}
+	if qm.FetchMode == models.FetchModeValues {
+		// The header rows tell the values mode which rows to fetch the formats of
+		first, count := getHeaderRows(qm)
+		fmt.Fprintf(&key, "|mode:%s|header:%d,%d,%t", qm.FetchMode, first, count, qm.NoHeader)
+	}
+	return key.String()
+}
+
+// getSheetData gets grid data corresponding to the ranges of a spreadsheet.
+func (gs *GoogleSheets) getSheetData(ctx context.Context, client client, qm *models.QueryModel) (*spreadsheetData, map[string]any, error) {
+	ranges := qm.GetRanges()
+	cacheKey := getSheetDataCacheKey(qm, ranges)
+	switch qm.CacheMode {
+	case "", models.CacheModeTTL, models.CacheModeModifiedTime, models.CacheModeStaleWhileRevalidate:
+	default:
+		return nil, nil, backend.DownstreamError(fmt.Errorf("unknown cache mode %q", qm.CacheMode))
+	}
+
+	sheetData, expires, found, err := gs.getCachedSheetData(cacheKey, qm)
+	if err != nil {
+		return nil, nil, err
+	}
+	var modifiedTime string
+	if found {
+		switch qm.CacheMode {
+		case models.CacheModeStaleWhileRevalidate:
+			// Stale data is served right away, while the next query gets the refreshed data
+			fresh, _ := getCacheDurations(qm)
+			expires = sheetData.FetchedAt.Add(fresh)
+			stale := time.Now().After(expires)
+			if stale {
+				gs.refreshSheetData(ctx, client, qm, ranges, cacheKey)
 			}
-			logger.Warn(apiErr.Error())
-			return nil, nil, err
+			return sheetData, map[string]any{
+				"hit":     true,
+				"expires": expires.Unix(),
+				"stale":   stale,
+			}, nil
+		case models.CacheModeModifiedTime:
+		default:
+			return sheetData, map[string]any{
+				"hit":     true,
+				"expires": expires.Unix(),
+			}, nil
+		}
+
+		// The cached data is only served while the spreadsheet is unchanged
+		modifiedTime, err = getModifiedTime(ctx, client, qm.Spreadsheet)
+		if err != nil || modifiedTime == sheetData.ModifiedTime {
+			if err != nil {
+				backend.Logger.FromContext(ctx).Warn("could not check the spreadsheet modified time, serving cached data", "error", err)
+			}
+			return sheetData, map[string]any{
+				"hit":       true,
+				"expires":   expires.Unix(),
+				"validated": err == nil,
+			}, nil
+		}
+	} else if qm.CacheDurationSeconds > 0 && qm.CacheMode == models.CacheModeModifiedTime {
+		// The modified time is read before the data, so that edits made while fetching are not missed
+		modifiedTime, err = getModifiedTime(ctx, client, qm.Spreadsheet)
+		if err != nil {
+			backend.Logger.FromContext(ctx).Warn("could not get the spreadsheet modified time", "error", err)
 		}
+	}
 
-		if backend.IsDownstreamHTTPError(err) {
-			errWithSource := backend.DownstreamError(err)
-			return nil, nil, errWithSource
+	// Concurrent queries of the same data, such as the panels of a dashboard, share a single fetch. The fetch
+	// doesn't end with the query that started it, and each query only waits for it until its own context ends.
+	fetched := false
+	fetch := gs.fetches.DoChan(cacheKey, func() (any, error) {
+		fetched = true
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+		return gs.fetchSheetData(ctx, client, qm, ranges, cacheKey, modifiedTime)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case result := <-fetch:
+		if result.Err != nil {
+			return nil, nil, result.Err
+		}
+		if result.Shared && !fetched {
+			coalescedFetches.Inc()
 		}
+		return result.Val.(*spreadsheetData), map[string]any{"hit": false}, nil
+	}
+}
+
+// getCachedSheetData returns the cached data of a query, with its expiration. The data is shared by the queries
+// of the same ranges, whatever their cache settings, so the data fetched longer ago than the query caches it
+// is not returned.
+func (gs *GoogleSheets) getCachedSheetData(cacheKey string, qm *models.QueryModel) (*spreadsheetData, time.Time, bool, error) {
+	if qm.CacheDurationSeconds <= 0 {
+		return nil, time.Time{}, false, nil
+	}
+	item, expires, found := gs.Cache.GetWithExpiration(cacheKey)
+	if !found {
+		return nil, time.Time{}, false, nil
+	}
+	sheetData, ok := item.(*spreadsheetData)
+	if !ok {
+		return nil, time.Time{}, false, errors.New("invalid cache item not type of *spreadsheetData")
+	}
+
+	_, cached := getCacheDurations(qm)
+	queryExpires := sheetData.FetchedAt.Add(cached)
+	if time.Now().After(queryExpires) {
+		return nil, time.Time{}, false, nil
+	}
+	if expires.IsZero() || queryExpires.Before(expires) {
+		expires = queryExpires
+	}
+	return sheetData, expires, true, nil
+}
+
+// refreshSheetData fetches the data of a query in the background, unless it is already being fetched.
+func (gs *GoogleSheets) refreshSheetData(ctx context.Context, client client, qm *models.QueryModel, ranges []string, cacheKey string) {
+	logger := backend.Logger.FromContext(ctx)
+	// The refresh outlives the query, whose result is never read
+	gs.fetches.DoChan(cacheKey, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+
+		sheetData, err := gs.fetchSheetData(ctx, client, qm, ranges, cacheKey, "")
+		if err != nil {
+			logger.Warn("could not refresh stale spreadsheet data", "spreadsheet", qm.Spreadsheet, "error", err)
+		}
+		return sheetData, err
+	})
+}
+
+// fetchSheetData fetches the data of the ranges of a spreadsheet, and caches it for the cache duration of the query.
+func (gs *GoogleSheets) fetchSheetData(ctx context.Context, client client, qm *models.QueryModel, ranges []string, cacheKey string, modifiedTime string) (*spreadsheetData, error) {
+	if qm.UnionSheets != "" {
+		var err error
+		ranges, err = getUnionRanges(ctx, client, qm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The wildcard range selects all sheets, which is what the API returns when no range is given
+	allSheets := slices.Contains(ranges, models.AllSheetsRange)
+	if allSheets {
+		ranges = nil
+	}
+
+	var (
+		sheetData *spreadsheetData
+		err       error
+	)
+	switch qm.FetchMode {
+	case "", models.FetchModeGrid:
+		sheetData, err = getGridSheetData(ctx, client, qm, ranges, allSheets)
+	case models.FetchModeValues:
+		sheetData, err = getValuesSheetData(ctx, client, qm, ranges, allSheets)
+	default:
+		err = backend.DownstreamError(fmt.Errorf("unknown fetch mode %q", qm.FetchMode))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sheetData.Grids) == 0 {
+		return nil, backend.DownstreamError(errors.New("no sheet data returned for the requested range"))
+	}
+	sheetData.ModifiedTime = modifiedTime
+	sheetData.FetchedAt = time.Now()
+
+	if qm.CacheDurationSeconds > 0 {
+		_, cached := getCacheDurations(qm)
+		gs.Cache.Set(cacheKey, sheetData, cached)
+	}
+	return sheetData, nil
+}
+
+// getModifiedTime gets the Drive modified time of a spreadsheet.
+func getModifiedTime(ctx context.Context, client client, spreadsheetID string) (string, error) {
+	file, err := client.GetFile(ctx, spreadsheetID)
+	if err != nil {
+		return "", err
+	}
+	return file.ModifiedTime, nil
+}
+
+// getGridSheetData gets the grid data of the ranges of a spreadsheet, with the formats of every cell.
+func getGridSheetData(ctx context.Context, client client, qm *models.QueryModel, ranges []string, allSheets bool) (*spreadsheetData, error) {
+	result, err := client.GetSpreadsheet(ctx, qm.Spreadsheet, ranges, true)
+	if err != nil {
+		return nil, handleGoogleAPIError(ctx, err)
+	}
 
-		netErr, neErrOk := err.(net.Error)
-		if neErrOk {
-			var retrieveErr *oauth2.RetrieveError
-			if errors.As(netErr, &retrieveErr) {
-				if backend.ErrorSourceFromHTTPStatus(retrieveErr.Response.StatusCode) == backend.ErrorSourceDownstream {
-					return nil, nil, backend.DownstreamError(err)
+	sheetData := &spreadsheetData{}
+	if result.Properties != nil {
+		sheetData.TimeZone = result.Properties.TimeZone
+	}
+	namedRanges := getQueriedNamedRanges(result, ranges)
+	rangeIndices := getGridRangeIndices(result, ranges)
+	for _, sheet := range result.Sheets {
+		title := ""
+		var indices []int
+		if sheet.Properties != nil {
+			title = sheet.Properties.Title
+			indices = rangeIndices[sheet.Properties.SheetId]
+		}
+		for i, gridData := range sheet.Data {
+			grid := &sheetGrid{Title: title, Data: gridData}
+			if i < len(indices) {
+				if namedRange := findQueriedNamedRange(namedRanges, ranges[indices[i]]); namedRange != nil {
+					grid.NamedRange = namedRange.Name
+					grid.ResolvedRange = getA1Range(title, namedRange.Range)
 				}
-				return nil, nil, err
 			}
+			sheetData.Grids = append(sheetData.Grids, grid)
+		}
+		// Without any range the API returns every sheet, but only the first one was asked for
+		if len(ranges) == 0 && !allSheets {
+			break
 		}
+	}
+	return sheetData, nil
+}
 
-		logger.Warn("unknown error", "err", err)
-		// This is an unknown error from the client - it might have error source middleware.
-		// If not, it will be handled by the default error source - plugin error.
-		return nil, nil, err
+// getQueriedNamedRanges returns the named ranges of the spreadsheet that are among the queried ranges.
+func getQueriedNamedRanges(spreadsheet *sheets.Spreadsheet, ranges []string) []*sheets.NamedRange {
+	namedRanges := []*sheets.NamedRange{}
+	for _, namedRange := range spreadsheet.NamedRanges {
+		if namedRange.Range != nil && slices.Contains(ranges, namedRange.Name) {
+			namedRanges = append(namedRanges, namedRange)
+		}
 	}
+	return namedRanges
+}
 
-	if result.Properties.TimeZone != "" {
-		loc, err := time.LoadLocation(result.Properties.TimeZone)
-		if err != nil {
-			logger.Warn("could not load timezone from spreadsheet: %w", err)
+// findQueriedNamedRange returns the named range with the given name, if any.
+func findQueriedNamedRange(namedRanges []*sheets.NamedRange, name string) *sheets.NamedRange {
+	for _, namedRange := range namedRanges {
+		if namedRange.Name == name {
+			return namedRange
+		}
+	}
+	return nil
+}
+
+// getGridRangeIndices returns the indices of the queried ranges by sheet id. The API returns the sheets in their
+// order, each with the grid data of its ranges in the order of the query, so the nth grid data of a sheet answers
+// the nth range of the sheet. Ranges without sheet refer to the first sheet.
+func getGridRangeIndices(spreadsheet *sheets.Spreadsheet, ranges []string) map[int64][]int {
+	sheetIDs := map[string]int64{}
+	var firstSheetID *int64
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties == nil {
+			continue
+		}
+		if firstSheetID == nil {
+			firstSheetID = &sheet.Properties.SheetId
+		}
+		sheetIDs[sheet.Properties.Title] = sheet.Properties.SheetId
+	}
+	if firstSheetID == nil {
+		return nil
+	}
+
+	indices := map[int64][]int{}
+	for i, r := range ranges {
+		sheetID := *firstSheetID
+		if namedRange := findQueriedNamedRange(spreadsheet.NamedRanges, r); namedRange != nil && namedRange.Range != nil {
+			sheetID = namedRange.Range.SheetId
 		} else {
-			time.Local = loc
+			// The range is a sheet, or cells of a sheet
+			title := r
+			if j := strings.LastIndex(r, "!"); j >= 0 {
+				title = r[:j]
+			}
+			if id, ok := sheetIDs[unquoteSheetTitle(title)]; ok {
+				sheetID = id
+			}
 		}
+		indices[sheetID] = append(indices[sheetID], i)
 	}
+	return indices
+}
 
-	sheetData := result.Sheets[0].Data[0]
-	if qm.CacheDurationSeconds > 0 {
-		gs.Cache.Set(cacheKey, sheetData, time.Duration(qm.CacheDurationSeconds)*time.Second)
+// handleGoogleAPIError converts an error returned by the Google APIs to an error with the right error source.
+func handleGoogleAPIError(ctx context.Context, err error) error {
+	logger := backend.Logger.FromContext(ctx)
+	if apiErr, ok := err.(*googleapi.Error); ok {
+		// Handle API-specific errors
+		// We use ErrorSourceFromHTTPStatus to determine error source based on HTTP status code
+		if apiErr.Code == 404 {
+			errWithSource := backend.DownstreamError(errors.New("spreadsheet not found"))
+			return errWithSource
+		}
+		if apiErr.Message != "" {
+			logger.Warn("Google API Error: " + apiErr.Message)
+			err := fmt.Errorf("google API Error %d", apiErr.Code)
+			if backend.ErrorSourceFromHTTPStatus(apiErr.Code) == backend.ErrorSourceDownstream {
+				return backend.DownstreamError(err)
+			}
+			return err
+		}
+
+		err := errors.New("unknown API error")
+		if backend.ErrorSourceFromHTTPStatus(apiErr.Code) == backend.ErrorSourceDownstream {
+			return backend.DownstreamError(err)
+		}
+		logger.Warn(apiErr.Error())
+		return err
+	}
+
+	if backend.IsDownstreamHTTPError(err) {
+		errWithSource := backend.DownstreamError(err)
+		return errWithSource
+	}
+
+	netErr, neErrOk := err.(net.Error)
+	if neErrOk {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(netErr, &retrieveErr) {
+			if backend.ErrorSourceFromHTTPStatus(retrieveErr.Response.StatusCode) == backend.ErrorSourceDownstream {
+				return backend.DownstreamError(err)
+			}
+			return err
+		}
+	}
+
+	logger.Warn("unknown error", "err", err)
+	// This is an unknown error from the client - it might have error source middleware.
+	// If not, it will be handled by the default error source - plugin error.
+	return err
+}
+
+// transformSheetsToDataFrames returns a data frame for each grid of the sheet data.
+// When several grids are returned each frame is named after its sheet.
+func (gs *GoogleSheets) transformSheetsToDataFrames(ctx context.Context, sheetData *spreadsheetData, loc *time.Location, meta map[string]any, refID string, qm *models.QueryModel) ([]*data.Frame, error) {
+	frames := make([]*data.Frame, 0, len(sheetData.Grids))
+	for _, grid := range sheetData.Grids {
+		frameMeta := maps.Clone(meta)
+		frameMeta["sheet"] = grid.Title
+		// Each of several ranges is told by its own range
+		if len(sheetData.Grids) > 1 || len(qm.Ranges) > 0 {
+			frameMeta["range"] = getGridDataRange(grid.Title, grid.Data)
+		}
+		if grid.NamedRange != "" {
+			frameMeta["namedRange"] = grid.NamedRange
+			frameMeta["resolvedRange"] = grid.ResolvedRange
+		}
+		frame, err := gs.transformSheetToDataFrame(ctx, grid.Data, loc, frameMeta, refID, qm)
+		if err != nil {
+			return nil, err
+		}
+		if len(sheetData.Grids) > 1 {
+			frame.Name = grid.Title
+		}
+		frames = append(frames, frame)
 	}
+	return frames, nil
+}
 
-	return sheetData, map[string]any{"hit": false}, nil
+// transformSheetToDataFrame returns a data frame for the grid data, reading times in the given location.
+func (gs *GoogleSheets) transformSheetToDataFrame(ctx context.Context, sheet *sheets.GridData, loc *time.Location, meta map[string]any, refID string, qm *models.QueryModel) (*data.Frame, error) {
+	return gs.transformGridToDataFrame(ctx, sheet, loc, meta, refID, qm, func(rowIndex, columnIndex int) string {
+		return getExcelColumnName(int(sheet.StartColumn)+columnIndex+1) + strconv.Itoa(int(sheet.StartRow)+rowIndex+1)
+	})
 }
 
-func (gs *GoogleSheets) transformSheetToDataFrame(ctx context.Context, sheet *sheets.GridData, meta map[string]any, refID string, qm *models.QueryModel) (*data.Frame, error) {
+// transformGridToDataFrame returns a data frame for the grid data, with cellRef returning the reference
+// of a cell of the grid in the warnings.
+func (gs *GoogleSheets) transformGridToDataFrame(ctx context.Context, sheet *sheets.GridData, loc *time.Location, meta map[string]any, refID string, qm *models.QueryModel, cellRef func(rowIndex, columnIndex int) string) (*data.Frame, error) {
 	logger := backend.Logger.FromContext(ctx)
-	columns, start := getColumnDefinitions(sheet.RowData)
+	columns, start := getColumnDefinitions(sheet.RowData, qm)
 	warnings := []string{}
 
+	converterMap := newConverterMap(loc, qm.TimeFormat)
 	converters := make([]data.FieldConverter, len(columns))
 	for i, column := range columns {
+		if override, ok := qm.ColumnTypes[column.Header]; ok {
+			columnType, err := ParseColumnType(override)
+			if err != nil {
+				return nil, backend.DownstreamError(fmt.Errorf("invalid type for column %q: %w", column.Header, err))
+			}
+			column.SetType(columnType)
+		}
+
 		fc, ok := converterMap[column.GetType()]
 		if !ok {
 			return nil, fmt.Errorf("unknown column type: %s", column.GetType())
@@ -207,8 +952,9 @@ func (gs *GoogleSheets) transformSheetToDataFrame(ctx context.Context, sheet *sh
 		}
 	}
 
-	// We want to show the warnings only once per column
+	// We want to show the warnings only once per column, unless the column type was forced
 	warningsIncludeConverterErrorForColumns := make(map[int]bool, len(columns))
+	cellWarnings := 0
 	for rowIndex := start; rowIndex < len(sheet.RowData); rowIndex++ {
 		for columnIndex, cellData := range sheet.RowData[rowIndex].Values {
 			if columnIndex >= len(columns) {
@@ -221,61 +967,91 @@ func (gs *GoogleSheets) transformSheetToDataFrame(ctx context.Context, sheet *sh
 			}
 
 			err := inputConverter.Set(columnIndex, rowIndex-start, cellData)
-			if err != nil && !warningsIncludeConverterErrorForColumns[columnIndex] {
+			if err == nil {
+				continue
+			}
+			if columns[columnIndex].HasForcedType() {
+				// Cells that fail to convert to a forced type are left as nulls
+				cellWarnings++
+				if cellWarnings <= maxCellWarnings {
+					cell := cellRef(rowIndex, columnIndex)
+					warnings = append(warnings, fmt.Sprintf("Cell %s in column %q was set to null: %s", cell, columns[columnIndex].Header, err.Error()))
+				}
+				continue
+			}
+			if !warningsIncludeConverterErrorForColumns[columnIndex] {
 				logger.Debug("unsuccessful converting of cell data", "err", err)
 				warnings = append(warnings, err.Error())
 				warningsIncludeConverterErrorForColumns[columnIndex] = true
 			}
 		}
 	}
+	if cellWarnings > maxCellWarnings {
+		warnings = append(warnings, fmt.Sprintf("%d more cells were set to null", cellWarnings-maxCellWarnings))
+	}
 
 	meta["warnings"] = warnings
 	meta["spreadsheetId"] = qm.Spreadsheet
-	meta["range"] = qm.Range
+	if _, ok := meta["range"]; !ok {
+		meta["range"] = qm.Range
+	}
 	frame.Meta = &data.FrameMeta{Custom: meta}
 	return frame, nil
 }
 
-// timeConverter handles sheets TIME column types.
-var timeConverter = data.FieldConverter{
-	OutputFieldType: data.FieldTypeNullableTime,
-	Converter: func(i any) (any, error) {
-		var t *time.Time
-		cellData, ok := i.(*sheets.CellData)
-		if !ok {
-			return t, fmt.Errorf("expected type *sheets.CellData, but got %T", i)
-		}
+// maxCellWarnings is the maximum number of warnings reported for cells that could not be converted.
+const maxCellWarnings = 100
 
-		switch {
-		// Convert time based on decimal "Number Value" if possible; format agnostic
-		case cellData.EffectiveValue != nil && cellData.EffectiveValue.NumberValue != nil:
-			const (
-				secondsPerDay        = 24 * 60 * 60
-				nanosecondsPerSecond = 1e9
-			)
-
-			// Dates are stored as decimal values where each whole number represents a day counted from December 30, 1899.
-			// See https://developers.google.com/workspace/sheets/api/guides/formats
-			decimalDateTime := *cellData.EffectiveValue.NumberValue
-			baseDate := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
-			days := int64(decimalDateTime)
-			calculatedDate := baseDate.AddDate(0, 0, int(days))
+// newTimeConverter returns a converter for sheets TIME column types, reading times in the given location.
+// When a layout is given, it is used to parse cells that don't hold a number value.
+func newTimeConverter(loc *time.Location, layout string) data.FieldConverter {
+	return data.FieldConverter{
+		OutputFieldType: data.FieldTypeNullableTime,
+		Converter: func(i any) (any, error) {
+			var t *time.Time
+			cellData, ok := i.(*sheets.CellData)
+			if !ok {
+				return t, fmt.Errorf("expected type *sheets.CellData, but got %T", i)
+			}
 
-			timeRemainder := decimalDateTime - float64(days)
+			switch {
+			// Convert time based on decimal "Number Value" if possible; format agnostic
+			case cellData.EffectiveValue != nil && cellData.EffectiveValue.NumberValue != nil:
+				// Dates are stored as decimal values where each whole number represents a day counted from December 30, 1899.
+				// See https://developers.google.com/workspace/sheets/api/guides/formats
+				calculatedDateTime := serialNumberToTime(*cellData.EffectiveValue.NumberValue, loc)
+				return &calculatedDateTime, nil
 
-			// Put calculated date and time remainder together
-			calculatedDateTime := calculatedDate.Add(time.Duration(timeRemainder * secondsPerDay * nanosecondsPerSecond))
-			return &calculatedDateTime, nil
+			case layout != "":
+				parsedTime, err := time.ParseInLocation(layout, strings.TrimSpace(cellData.FormattedValue), loc)
+				if err != nil {
+					return t, fmt.Errorf("error while parsing date '%v' with layout '%v'", cellData.FormattedValue, layout)
+				}
+				return &parsedTime, nil
 
-		// Else, fallback to the old parsing for backwards compatibility
-		default:
-			parsedTime, err := dateparse.ParseLocal(cellData.FormattedValue)
-			if err != nil {
-				return t, fmt.Errorf("error while parsing date '%v'", cellData.FormattedValue)
+			// Else, fallback to the old parsing for backwards compatibility
+			default:
+				parsedTime, err := dateparse.ParseIn(cellData.FormattedValue, loc)
+				if err != nil {
+					return t, fmt.Errorf("error while parsing date '%v'", cellData.FormattedValue)
+				}
+				return &parsedTime, nil
 			}
-			return &parsedTime, nil
-		}
-	},
+		},
+	}
+}
+
+// serialNumberToTime converts a spreadsheet serial number to a time. The whole part counts days from
+// December 30, 1899 and the fraction is the time of the day, both being wall clock values in the location.
+// Dates without time are therefore midnight in the location, and days of DST transitions keep their wall clock time.
+func serialNumberToTime(serial float64, loc *time.Location) time.Time {
+	const millisecondsPerDay = 24 * 60 * 60 * 1000
+
+	days := math.Floor(serial)
+	// Round to the millisecond to absorb the floating point error of the fraction
+	milliseconds := int(math.Round((serial - days) * millisecondsPerDay))
+
+	return time.Date(1899, time.December, 30+int(days), 0, 0, 0, milliseconds*int(time.Millisecond), loc)
 }
 
 // stringConverter handles sheets STRING column types.
@@ -291,24 +1067,57 @@ var stringConverter = data.FieldConverter{
 	},
 }
 
-// numberConverter handles sheets STRING column types.
+// numberConverter handles sheets NUMBER column types.
+// Cells without a number value are parsed from their formatted value.
 var numberConverter = data.FieldConverter{
 	OutputFieldType: data.FieldTypeNullableFloat64,
 	Converter: func(i any) (any, error) {
+		var f *float64
 		cellData, ok := i.(*sheets.CellData)
 		if !ok {
-			return nil, fmt.Errorf("expected type *sheets.CellData, but got %T", i)
+			return f, fmt.Errorf("expected type *sheets.CellData, but got %T", i)
+		}
+		if cellData.EffectiveValue != nil && cellData.EffectiveValue.NumberValue != nil {
+			return cellData.EffectiveValue.NumberValue, nil
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(cellData.FormattedValue), 64)
+		if err != nil {
+			return f, fmt.Errorf("error while parsing number '%v'", cellData.FormattedValue)
+		}
+		return &value, nil
+	},
+}
+
+// booleanConverter handles sheets BOOLEAN column types.
+// Cells without a boolean value are parsed from their formatted value.
+var booleanConverter = data.FieldConverter{
+	OutputFieldType: data.FieldTypeNullableBool,
+	Converter: func(i any) (any, error) {
+		var b *bool
+		cellData, ok := i.(*sheets.CellData)
+		if !ok {
+			return b, fmt.Errorf("expected type *sheets.CellData, but got %T", i)
+		}
+		if cellData.EffectiveValue != nil && cellData.EffectiveValue.BoolValue != nil {
+			return cellData.EffectiveValue.BoolValue, nil
+		}
+		value, err := strconv.ParseBool(strings.ToLower(strings.TrimSpace(cellData.FormattedValue)))
+		if err != nil {
+			return b, fmt.Errorf("error while parsing boolean '%v'", cellData.FormattedValue)
 		}
-		return cellData.EffectiveValue.NumberValue, nil
+		return &value, nil
 	},
 }
 
-// converterMap is a map sheets.ColumnType to fieldConverter and
+// newConverterMap returns a map of sheets.ColumnType to fieldConverter, which
 // is used to create a data.FrameInputConverter for a returned sheet.
-var converterMap = map[ColumnType]data.FieldConverter{
-	"TIME":   timeConverter,
-	"STRING": stringConverter,
-	"NUMBER": numberConverter,
+func newConverterMap(loc *time.Location, timeLayout string) map[ColumnType]data.FieldConverter {
+	return map[ColumnType]data.FieldConverter{
+		"TIME":    newTimeConverter(loc, timeLayout),
+		"STRING":  stringConverter,
+		"NUMBER":  numberConverter,
+		"BOOLEAN": booleanConverter,
+	}
 }
 
 func getUniqueColumnName(formattedName string, columnIndex int, columns map[string]bool) string {
@@ -331,28 +1140,78 @@ func getUniqueColumnName(formattedName string, columnIndex int, columns map[stri
 	return name
 }
 
-func getColumnDefinitions(rows []*sheets.RowData) ([]*ColumnDefinition, int) {
+// getHeaderRows returns the index of the first header row of the query and the number of header rows.
+func getHeaderRows(qm *models.QueryModel) (int, int) {
+	return max(qm.HeaderRowIndex, 0), max(qm.HeaderRowCount, 1)
+}
+
+// getColumnHeaders returns the unique column names of the rows and the index of the first data row.
+// Rows above the header rows are skipped, and several header rows are flattened into a single name.
+func getColumnHeaders(rows []*sheets.RowData, qm *models.QueryModel) ([]string, int) {
 	if len(rows) < 1 {
-		return []*ColumnDefinition{}, 0
+		return []string{}, 0
 	}
-	headerRow := rows[0].Values
+	first, count := getHeaderRows(qm)
 
-	columns := make([]*ColumnDefinition, 0, len(headerRow))
-	columnMap := make(map[string]bool, len(headerRow))
-	start := 0
-	if len(rows) > 1 {
-		start = 1
-		for columnIndex, headerCell := range headerRow {
-			name := getUniqueColumnName(strings.TrimSpace(headerCell.FormattedValue), columnIndex, columnMap)
-			columnMap[name] = true
-			columns = append(columns, NewColumnDefinition(name, columnIndex))
+	// Without header, or when the header rows would leave no data, every row is data and the names are generated
+	if qm.NoHeader || len(rows) <= first+count {
+		start := min(first, len(rows)-1)
+		width := 0
+		for _, row := range rows[start:] {
+			width = max(width, len(row.Values))
 		}
-	} else {
-		for columnIndex := range headerRow {
+		names := make([]string, 0, width)
+		columnMap := make(map[string]bool, width)
+		for columnIndex := 0; columnIndex < width; columnIndex++ {
 			name := getUniqueColumnName("", columnIndex, columnMap)
 			columnMap[name] = true
-			columns = append(columns, NewColumnDefinition(name, columnIndex))
+			names = append(names, name)
 		}
+		return names, start
+	}
+
+	headerRows := rows[first : first+count]
+	width := 0
+	for _, row := range headerRows {
+		width = max(width, len(row.Values))
+	}
+
+	names := make([]string, 0, width)
+	columnMap := make(map[string]bool, width)
+	// Merged cells only hold a value in their first column, so blank cells of the upper
+	// header rows inherit the last value found to their left
+	parents := make([]string, len(headerRows))
+	for columnIndex := 0; columnIndex < width; columnIndex++ {
+		parts := make([]string, 0, len(headerRows))
+		for i, row := range headerRows {
+			value := ""
+			if columnIndex < len(row.Values) && row.Values[columnIndex] != nil {
+				value = strings.TrimSpace(row.Values[columnIndex].FormattedValue)
+			}
+			if i < len(headerRows)-1 {
+				if value == "" {
+					value = parents[i]
+				} else {
+					parents[i] = value
+				}
+			}
+			if value != "" && (len(parts) == 0 || parts[len(parts)-1] != value) {
+				parts = append(parts, value)
+			}
+		}
+		name := getUniqueColumnName(strings.Join(parts, " / "), columnIndex, columnMap)
+		columnMap[name] = true
+		names = append(names, name)
+	}
+
+	return names, first + count
+}
+
+func getColumnDefinitions(rows []*sheets.RowData, qm *models.QueryModel) ([]*ColumnDefinition, int) {
+	names, start := getColumnHeaders(rows, qm)
+	columns := make([]*ColumnDefinition, 0, len(names))
+	for columnIndex, name := range names {
+		columns = append(columns, NewColumnDefinition(name, columnIndex))
 	}
 
 	// Check the types for each column
@@ -3,6 +3,7 @@ package googlesheets
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/google-sheets-datasource/pkg/models"
 
@@ -22,7 +23,7 @@ func BenchmarkTransformMixedSheetToDataFrame(b *testing.B) {
 	meta := make(map[string]any)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], meta, "ref1", &qm)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], time.UTC, meta, "ref1", &qm)
 		require.NoError(b, err)
 		Frame = frame
 	}
@@ -36,7 +37,7 @@ func BenchmarkTransformMixedSheetWithInvalidDateToDataFrame(b *testing.B) {
 	meta := make(map[string]any)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], meta, "ref1", &qm)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], time.UTC, meta, "ref1", &qm)
 		require.NoError(b, err)
 		Frame = frame
 	}
@@ -3,10 +3,12 @@ package googlesheets
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,10 +18,13 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/sheets/v4"
 )
@@ -28,14 +33,30 @@ type fakeClient struct {
 	mock.Mock
 }
 
-func (f *fakeClient) GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRange string, includeGridData bool) (*sheets.Spreadsheet, error) {
-	args := f.Called(ctx, spreadSheetID, sheetRange, includeGridData)
+func (f *fakeClient) GetSpreadsheet(ctx context.Context, spreadSheetID string, sheetRanges []string, includeGridData bool) (*sheets.Spreadsheet, error) {
+	args := f.Called(ctx, spreadSheetID, sheetRanges, includeGridData)
 	if spreadsheet, ok := args.Get(0).(*sheets.Spreadsheet); ok {
 		return spreadsheet, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+func (f *fakeClient) GetValues(ctx context.Context, spreadSheetID string, sheetRanges []string) (*sheets.BatchGetValuesResponse, error) {
+	args := f.Called(ctx, spreadSheetID, sheetRanges)
+	if values, ok := args.Get(0).(*sheets.BatchGetValuesResponse); ok {
+		return values, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (f *fakeClient) GetFile(ctx context.Context, fileID string) (*drive.File, error) {
+	args := f.Called(ctx, fileID)
+	if file, ok := args.Get(0).(*drive.File); ok {
+		return file, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func loadTestSheet(path string) (*sheets.Spreadsheet, error) {
 	jsonBody, err := os.ReadFile(path)
 	if err != nil {
@@ -50,6 +71,43 @@ func loadTestSheet(path string) (*sheets.Spreadsheet, error) {
 	return &sheet, nil
 }
 
+// newTestSpreadsheet returns a spreadsheet with a small grid for each of the given sheet titles.
+func newTestSpreadsheet(titles ...string) *sheets.Spreadsheet {
+	spreadsheet := &sheets.Spreadsheet{Properties: &sheets.SpreadsheetProperties{}}
+	for _, title := range titles {
+		value := title
+		spreadsheet.Sheets = append(spreadsheet.Sheets, &sheets.Sheet{
+			Properties: &sheets.SheetProperties{Title: title},
+			Data: []*sheets.GridData{{RowData: []*sheets.RowData{
+				{Values: []*sheets.CellData{{FormattedValue: "Region"}}},
+				{Values: []*sheets.CellData{{FormattedValue: title, EffectiveValue: &sheets.ExtendedValue{StringValue: &value}}}},
+			}}},
+		})
+	}
+	return spreadsheet
+}
+
+// newTestGrid returns grid data with a cell holding each of the given formatted values.
+func newTestGrid(rows ...[]string) *sheets.GridData {
+	grid := &sheets.GridData{}
+	for _, row := range rows {
+		rowData := &sheets.RowData{}
+		for _, value := range row {
+			rowData.Values = append(rowData.Values, &sheets.CellData{FormattedValue: value})
+		}
+		grid.RowData = append(grid.RowData, rowData)
+	}
+	return grid
+}
+
+// getCounterValue returns the current value of a counter metric.
+func getCounterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
 func TestGooglesheets(t *testing.T) {
 	t.Run("getUniqueColumnName", func(t *testing.T) {
 		t.Run("name is appended with number if not unique", func(t *testing.T) {
@@ -65,6 +123,45 @@ func TestGooglesheets(t *testing.T) {
 		})
 	})
 
+	t.Run("getColumnHeaders", func(t *testing.T) {
+		t.Run("first row is the header by default", func(t *testing.T) {
+			names, start := getColumnHeaders(newTestGrid([]string{"Date", "Value"}, []string{"2026-01-01", "1"}).RowData, &models.QueryModel{})
+			assert.Equal(t, []string{"Date", "Value"}, names)
+			assert.Equal(t, 1, start)
+		})
+
+		t.Run("single row is data", func(t *testing.T) {
+			names, start := getColumnHeaders(newTestGrid([]string{"2026-01-01", "1"}).RowData, &models.QueryModel{})
+			assert.Equal(t, []string{"Field 1", "Field 2"}, names)
+			assert.Equal(t, 0, start)
+		})
+
+		t.Run("rows above the header row are skipped", func(t *testing.T) {
+			rows := newTestGrid([]string{"Sales report"}, []string{}, []string{"Date", "Value"}, []string{"2026-01-01", "1"}).RowData
+			names, start := getColumnHeaders(rows, &models.QueryModel{HeaderRowIndex: 2})
+			assert.Equal(t, []string{"Date", "Value"}, names)
+			assert.Equal(t, 3, start)
+		})
+
+		t.Run("multiple header rows are flattened", func(t *testing.T) {
+			rows := newTestGrid(
+				[]string{"Date", "Q1", "", "Q2", ""},
+				[]string{"", "Revenue", "Cost", "Revenue", "Cost"},
+				[]string{"2026-01-01", "1", "2", "3", "4"},
+			).RowData
+			names, start := getColumnHeaders(rows, &models.QueryModel{HeaderRowCount: 2})
+			assert.Equal(t, []string{"Date", "Q1 / Revenue", "Q1 / Cost", "Q2 / Revenue", "Q2 / Cost"}, names)
+			assert.Equal(t, 2, start)
+		})
+
+		t.Run("no header generates the names", func(t *testing.T) {
+			rows := newTestGrid([]string{"2026-01-01", "1"}, []string{"2026-01-02", "2", "extra"}).RowData
+			names, start := getColumnHeaders(rows, &models.QueryModel{NoHeader: true})
+			assert.Equal(t, []string{"Field 1", "Field 2", "Field 3"}, names)
+			assert.Equal(t, 0, start)
+		})
+	})
+
 	t.Run("getSheetData", func(t *testing.T) {
 		t.Run("spreadsheets get cached", func(t *testing.T) {
 			client := &fakeClient{}
@@ -74,7 +171,7 @@ func TestGooglesheets(t *testing.T) {
 			}
 			require.Equal(t, 0, gsd.Cache.ItemCount())
 
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(loadTestSheet("./testdata/mixed-data.json"))
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json"))
 
 			_, meta, err := gsd.getSheetData(context.Background(), client, &qm)
 			require.NoError(t, err)
@@ -97,7 +194,7 @@ func TestGooglesheets(t *testing.T) {
 			}
 			require.Equal(t, 0, gsd.Cache.ItemCount())
 
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(loadTestSheet("./testdata/mixed-data.json"))
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json"))
 
 			_, meta, err := gsd.getSheetData(context.Background(), client, &qm)
 			require.NoError(t, err)
@@ -107,6 +204,267 @@ func TestGooglesheets(t *testing.T) {
 			client.AssertExpectations(t)
 		})
 
+		t.Run("modifiedTime cache mode refetches edited spreadsheets", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 3600, CacheMode: models.CacheModeModifiedTime}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetFile", context.Background(), qm.Spreadsheet).Return(&drive.File{ModifiedTime: "2026-10-01T10:00:00Z"}, nil).Twice()
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json")).Twice()
+
+			sheetData, meta, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.False(t, meta["hit"].(bool))
+			assert.Equal(t, "2026-10-01T10:00:00Z", sheetData.ModifiedTime)
+
+			_, meta, err = gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.True(t, meta["hit"].(bool))
+			assert.True(t, meta["validated"].(bool))
+
+			client.On("GetFile", context.Background(), qm.Spreadsheet).Return(&drive.File{ModifiedTime: "2026-10-01T10:05:00Z"}, nil).Twice()
+			sheetData, meta, err = gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.False(t, meta["hit"].(bool))
+			assert.Equal(t, "2026-10-01T10:05:00Z", sheetData.ModifiedTime)
+
+			_, meta, err = gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.True(t, meta["hit"].(bool))
+			client.AssertExpectations(t)
+		})
+
+		t.Run("modifiedTime cache mode serves cached data when Drive fails", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 3600, CacheMode: models.CacheModeModifiedTime}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetFile", context.Background(), qm.Spreadsheet).Return(&drive.File{ModifiedTime: "2026-10-01T10:00:00Z"}, nil).Once()
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json")).Once()
+			_, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+
+			client.On("GetFile", context.Background(), qm.Spreadsheet).Return(nil, errors.New("drive unavailable")).Once()
+			_, meta, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.True(t, meta["hit"].(bool))
+			assert.False(t, meta["validated"].(bool))
+			client.AssertExpectations(t)
+		})
+
+		t.Run("staleWhileRevalidate cache mode serves stale data and refreshes it", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 60, CacheMode: models.CacheModeStaleWhileRevalidate}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json")).Twice()
+
+			sheetData, meta, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.False(t, meta["hit"].(bool))
+
+			// The data is cached for its freshness and the default staleness window
+			_, expires, found := gsd.Cache.GetWithExpiration(getSheetDataCacheKey(&qm, qm.GetRanges()))
+			require.True(t, found)
+			assert.WithinDuration(t, sheetData.FetchedAt.Add(time.Minute+defaultMaxStale), expires, time.Second)
+
+			_, meta, err = gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.True(t, meta["hit"].(bool))
+			assert.False(t, meta["stale"].(bool))
+
+			// The data expires
+			sheetData.FetchedAt = sheetData.FetchedAt.Add(-2 * time.Minute)
+			staleData, meta, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.Same(t, sheetData, staleData)
+			assert.True(t, meta["hit"].(bool))
+			assert.True(t, meta["stale"].(bool))
+
+			assert.Eventually(t, func() bool {
+				item, found := gsd.Cache.Get(getSheetDataCacheKey(&qm, qm.GetRanges()))
+				return found && item != sheetData
+			}, time.Second, 10*time.Millisecond)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("cached data is only served while fresh for the query reading it", func(t *testing.T) {
+			client := &fakeClient{}
+			swrQuery := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 60, CacheMode: models.CacheModeStaleWhileRevalidate}
+			ttlQuery := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 60}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+			client.On("GetSpreadsheet", mock.Anything, ttlQuery.Spreadsheet, ttlQuery.GetRanges(), true).Return(loadTestSheet("./testdata/mixed-data.json")).Twice()
+
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &swrQuery)
+			require.NoError(t, err)
+
+			// The data is stale, which the staleWhileRevalidate query serves but not the ttl query
+			sheetData.FetchedAt = sheetData.FetchedAt.Add(-2 * time.Minute)
+			ttlData, meta, err := gsd.getSheetData(context.Background(), client, &ttlQuery)
+			require.NoError(t, err)
+			assert.False(t, meta["hit"].(bool))
+			assert.NotSame(t, sheetData, ttlData)
+
+			_, meta, err = gsd.getSheetData(context.Background(), client, &ttlQuery)
+			require.NoError(t, err)
+			assert.True(t, meta["hit"].(bool))
+			assert.WithinDuration(t, ttlData.FetchedAt.Add(time.Minute), time.Unix(meta["expires"].(int64), 0), time.Second)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("staleWhileRevalidate cache mode bounds the staleness window", func(t *testing.T) {
+			qm := models.QueryModel{CacheDurationSeconds: 60, CacheMode: models.CacheModeStaleWhileRevalidate, MaxStaleSeconds: 30}
+			fresh, cached := getCacheDurations(&qm)
+			assert.Equal(t, time.Minute, fresh)
+			assert.Equal(t, 90*time.Second, cached)
+
+			qm.CacheMode = models.CacheModeTTL
+			fresh, cached = getCacheDurations(&qm)
+			assert.Equal(t, time.Minute, fresh)
+			assert.Equal(t, time.Minute, cached)
+		})
+
+		t.Run("concurrent fetches of the same data are coalesced", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 10}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			// The fetch is held until all the queries are waiting for it
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).
+				Return(loadTestSheet("./testdata/mixed-data.json")).
+				WaitUntil(time.After(100 * time.Millisecond)).
+				Once()
+
+			before := getCounterValue(t, coalescedFetches)
+			var wg sync.WaitGroup
+			for range 5 {
+				wg.Go(func() {
+					sheetData, meta, err := gsd.getSheetData(context.Background(), client, &qm)
+					assert.NoError(t, err)
+					assert.NotNil(t, sheetData)
+					assert.False(t, meta["hit"].(bool))
+				})
+			}
+			wg.Wait()
+
+			assert.Equal(t, float64(4), getCounterValue(t, coalescedFetches)-before)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("shared fetches outlive the query that started them", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 10}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).
+				Return(loadTestSheet("./testdata/mixed-data.json")).
+				WaitUntil(time.After(100 * time.Millisecond)).
+				Once()
+
+			// The first query gives up before the fetch ends, which the second query still waits for
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			var wg sync.WaitGroup
+			wg.Go(func() {
+				_, _, err := gsd.getSheetData(ctx, client, &qm)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			})
+			time.Sleep(10 * time.Millisecond)
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			wg.Wait()
+
+			require.NoError(t, err)
+			assert.NotNil(t, sheetData)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("unknown cache mode is a downstream error", func(t *testing.T) {
+			qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheMode: "forever"}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+			_, _, err := gsd.getSheetData(context.Background(), &fakeClient{}, &qm)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		})
+
+		t.Run("multiple ranges return a grid per range", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Ranges: []string{"North!A1:B", "South!A1:B"}, Spreadsheet: "someId"}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.Ranges, true).Return(newTestSpreadsheet("North", "South"), nil)
+
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			require.Len(t, sheetData.Grids, 2)
+			assert.Equal(t, "North", sheetData.Grids[0].Title)
+			assert.Equal(t, "South", sheetData.Grids[1].Title)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("wildcard range returns all sheets", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Ranges: []string{models.AllSheetsRange}, Spreadsheet: "someId"}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, []string(nil), true).Return(newTestSpreadsheet("North", "South", "East"), nil)
+
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			require.Len(t, sheetData.Grids, 3)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("empty range returns only the first sheet", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Spreadsheet: "someId"}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, []string(nil), true).Return(newTestSpreadsheet("North", "South"), nil)
+
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			require.Len(t, sheetData.Grids, 1)
+			assert.Equal(t, "North", sheetData.Grids[0].Title)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("spreadsheet timezone is kept with the data", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := models.QueryModel{Range: "A1:B", Spreadsheet: "someId"}
+			gsd := &GoogleSheets{
+				Cache: cache.New(300*time.Second, 50*time.Second),
+			}
+			spreadsheet := newTestSpreadsheet("Sheet1")
+			spreadsheet.Properties.TimeZone = "Asia/Tokyo"
+			local := time.Local
+
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(spreadsheet, nil)
+
+			sheetData, _, err := gsd.getSheetData(context.Background(), client, &qm)
+			require.NoError(t, err)
+			assert.Equal(t, "Asia/Tokyo", sheetData.TimeZone)
+			assert.Same(t, local, time.Local)
+		})
+
 		t.Run("api error 404", func(t *testing.T) {
 			client := &fakeClient{}
 			qm := &models.QueryModel{
@@ -117,7 +475,7 @@ func TestGooglesheets(t *testing.T) {
 			gsd := &GoogleSheets{
 				Cache: cache.New(300*time.Second, 50*time.Second),
 			}
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
 				Code:    404,
 				Message: "Not found",
 			})
@@ -139,7 +497,7 @@ func TestGooglesheets(t *testing.T) {
 			gsd := &GoogleSheets{
 				Cache: cache.New(300*time.Second, 50*time.Second),
 			}
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
 				Code:    403,
 				Message: "Forbidden",
 			})
@@ -162,7 +520,7 @@ func TestGooglesheets(t *testing.T) {
 			gsd := &GoogleSheets{
 				Cache: cache.New(300*time.Second, 50*time.Second),
 			}
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, context.Canceled)
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, context.Canceled)
 
 			_, _, err := gsd.getSheetData(context.Background(), client, qm)
 
@@ -184,7 +542,7 @@ func TestGooglesheets(t *testing.T) {
 				Cache: cache.New(300*time.Second, 50*time.Second),
 			}
 
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, &net.OpError{Err: context.DeadlineExceeded})
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, &net.OpError{Err: context.DeadlineExceeded})
 
 			_, _, err := gsd.getSheetData(context.Background(), client, qm)
 
@@ -221,7 +579,7 @@ func TestGooglesheets(t *testing.T) {
 				Err: retrieveErr,
 			}
 
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, urlErr)
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, urlErr)
 
 			_, _, err := gsd.getSheetData(context.Background(), client, qm)
 
@@ -242,7 +600,7 @@ func TestGooglesheets(t *testing.T) {
 				Cache: cache.New(300*time.Second, 50*time.Second),
 			}
 
-			client.On("GetSpreadsheet", context.Background(), qm.Spreadsheet, qm.Range, true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, qm.GetRanges(), true).Return(&sheets.Spreadsheet{}, &googleapi.Error{
 				Message: "",
 			})
 
@@ -255,6 +613,154 @@ func TestGooglesheets(t *testing.T) {
 		})
 	})
 
+	t.Run("getSheets", func(t *testing.T) {
+		client := &fakeClient{}
+		gsd := &GoogleSheets{
+			Cache: cache.New(300*time.Second, 50*time.Second),
+		}
+		spreadsheet := newTestSpreadsheet("Summary", "Archive")
+		spreadsheet.Sheets[1].Properties.SheetId = 42
+		spreadsheet.Sheets[1].Properties.Index = 1
+		spreadsheet.Sheets[1].Properties.Hidden = true
+		spreadsheet.Sheets[1].Properties.GridProperties = &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}
+
+		client.On("GetSpreadsheet", context.Background(), "someId", []string(nil), false).Return(spreadsheet, nil).Once()
+
+		sheetInfos, err := gsd.getSheets(context.Background(), client, "someId")
+		require.NoError(t, err)
+		assert.Equal(t, []SheetInfo{
+			{Title: "Summary"},
+			{Title: "Archive", SheetID: 42, Index: 1, Hidden: true, RowCount: 1000, ColumnCount: 26},
+		}, sheetInfos)
+
+		t.Run("sheets are cached", func(t *testing.T) {
+			cached, err := gsd.getSheets(context.Background(), client, "someId")
+			require.NoError(t, err)
+			assert.Equal(t, sheetInfos, cached)
+			client.AssertExpectations(t)
+		})
+	})
+
+	t.Run("getSpreadsheetInfos", func(t *testing.T) {
+		client := &fakeClient{}
+		gsd := &GoogleSheets{
+			Cache: cache.New(300*time.Second, 50*time.Second),
+		}
+		client.On("GetFile", context.Background(), "reports").Return(&drive.File{Id: "reports", Name: "Reports", Parents: []string{"root"}}, nil).Once()
+		client.On("GetFile", context.Background(), "root").Return(&drive.File{Id: "root", Name: "My Drive"}, nil).Once()
+		client.On("GetFile", context.Background(), "shared").Return(nil, errors.New("not found")).Once()
+
+		spreadsheetInfos := gsd.getSpreadsheetInfos(context.Background(), client, []*drive.File{
+			{Id: "3", Name: "Metrics", ModifiedTime: "2026-03-01T10:00:00Z", Parents: []string{"reports"}},
+			{Id: "2", Name: "Metrics", ModifiedTime: "2026-03-02T10:00:00Z", Parents: []string{"shared"}},
+			{Id: "1", Name: "Metrics", ModifiedTime: "2026-03-01T10:00:00Z", Parents: []string{"reports"}, Owners: []*drive.User{{DisplayName: "Alex"}}},
+			{Id: "4", Name: "Archive"},
+		})
+		assert.Equal(t, []SpreadsheetInfo{
+			{ID: "2", Name: "Metrics", ModifiedTime: "2026-03-02T10:00:00Z"},
+			{ID: "1", Name: "Metrics", ModifiedTime: "2026-03-01T10:00:00Z", Owner: "Alex", ParentPath: "My Drive/Reports"},
+			{ID: "3", Name: "Metrics", ModifiedTime: "2026-03-01T10:00:00Z", ParentPath: "My Drive/Reports"},
+			{ID: "4", Name: "Archive"},
+		}, spreadsheetInfos)
+		// Folders are cached
+		client.AssertExpectations(t)
+		_, expires, found := gsd.Cache.GetWithExpiration("folder:reports")
+		require.True(t, found)
+		assert.WithinDuration(t, time.Now().Add(folderCacheDuration), expires, time.Second)
+
+		t.Run("folders that can't be read are cached", func(t *testing.T) {
+			spreadsheetInfos := gsd.getSpreadsheetInfos(context.Background(), client, []*drive.File{
+				{Id: "2", Name: "Metrics", Parents: []string{"shared"}},
+			})
+			assert.Equal(t, []SpreadsheetInfo{{ID: "2", Name: "Metrics"}}, spreadsheetInfos)
+			client.AssertExpectations(t)
+		})
+	})
+
+	t.Run("getRanges", func(t *testing.T) {
+		client := &fakeClient{}
+		gsd := &GoogleSheets{
+			Cache: cache.New(300*time.Second, 50*time.Second),
+		}
+		spreadsheet := newTestSpreadsheet("Summary", "Bob's data")
+		spreadsheet.Sheets[1].Properties.SheetId = 42
+		spreadsheet.NamedRanges = []*sheets.NamedRange{
+			{Name: "KPI_Table", NamedRangeId: "kpi", Range: &sheets.GridRange{SheetId: 42, StartRowIndex: 1, EndRowIndex: 10, EndColumnIndex: 3}},
+		}
+		spreadsheet.Sheets[0].ProtectedRanges = []*sheets.ProtectedRange{
+			{ProtectedRangeId: 7, Description: "Headers", WarningOnly: true, Range: &sheets.GridRange{EndRowIndex: 1}},
+		}
+
+		client.On("GetSpreadsheet", context.Background(), "someId", []string(nil), false).Return(spreadsheet, nil).Once()
+
+		rangesInfo, err := gsd.getRanges(context.Background(), client, "someId")
+		require.NoError(t, err)
+		assert.Equal(t, []NamedRange{{Name: "KPI_Table", NamedRangeID: "kpi", Range: "'Bob''s data'!A2:C10"}}, rangesInfo.NamedRanges)
+		assert.Equal(t, []ProtectedRange{{ProtectedRangeID: 7, Description: "Headers", Range: "'Summary'!1:1", WarningOnly: true}}, rangesInfo.ProtectedRanges)
+
+		t.Run("ranges are cached", func(t *testing.T) {
+			cached, err := gsd.getRanges(context.Background(), client, "someId")
+			require.NoError(t, err)
+			assert.Equal(t, rangesInfo, cached)
+			client.AssertExpectations(t)
+		})
+	})
+
+	t.Run("named range is resolved in frame meta", func(t *testing.T) {
+		client := &fakeClient{}
+		gsd := &GoogleSheets{
+			Cache: cache.New(300*time.Second, 50*time.Second),
+		}
+		qm := &models.QueryModel{Spreadsheet: "someId", Range: "KPI_Table"}
+		spreadsheet := newTestSpreadsheet("Summary")
+		spreadsheet.Sheets[0].Properties.SheetId = 42
+		spreadsheet.Sheets[0].Data[0].StartRow = 4
+		spreadsheet.Sheets[0].Data[0].StartColumn = 1
+		spreadsheet.NamedRanges = []*sheets.NamedRange{
+			{Name: "Other", Range: &sheets.GridRange{SheetId: 42, EndRowIndex: 2, EndColumnIndex: 2}},
+			{Name: "KPI_Table", Range: &sheets.GridRange{SheetId: 42, StartRowIndex: 4, EndRowIndex: 6, StartColumnIndex: 1, EndColumnIndex: 2}},
+		}
+
+		client.On("GetSpreadsheet", mock.Anything, "someId", []string{"KPI_Table"}, true).Return(spreadsheet, nil)
+
+		sheetData, meta, err := gsd.getSheetData(context.Background(), client, qm)
+		require.NoError(t, err)
+		require.Len(t, sheetData.Grids, 1)
+
+		frames, err := gsd.transformSheetsToDataFrames(context.Background(), sheetData, time.UTC, meta, "ref1", qm)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		frameMeta := frames[0].Meta.Custom.(map[string]any)
+		assert.Equal(t, "KPI_Table", frameMeta["namedRange"])
+		assert.Equal(t, "'Summary'!B5:B6", frameMeta["resolvedRange"])
+	})
+
+	t.Run("named ranges are matched to the grid data by position", func(t *testing.T) {
+		client := &fakeClient{}
+		ranges := []string{"Summary!B5:B6", "KPI_Table", "'Archive'!A1:A2", "Other"}
+		qm := &models.QueryModel{Spreadsheet: "someId", Ranges: ranges}
+		spreadsheet := newTestSpreadsheet("Archive", "Summary")
+		spreadsheet.Sheets[1].Properties.SheetId = 42
+		// The A1 range and both named ranges start at B5
+		spreadsheet.Sheets[1].Data = []*sheets.GridData{{StartRow: 4, StartColumn: 1}, {StartRow: 4, StartColumn: 1}, {StartRow: 4, StartColumn: 1}}
+		spreadsheet.NamedRanges = []*sheets.NamedRange{
+			{Name: "Other", Range: &sheets.GridRange{SheetId: 42, StartRowIndex: 4, EndRowIndex: 8, StartColumnIndex: 1, EndColumnIndex: 3}},
+			{Name: "KPI_Table", Range: &sheets.GridRange{SheetId: 42, StartRowIndex: 4, EndRowIndex: 6, StartColumnIndex: 1, EndColumnIndex: 2}},
+		}
+		client.On("GetSpreadsheet", mock.Anything, "someId", ranges, true).Return(spreadsheet, nil)
+
+		sheetData, err := getGridSheetData(context.Background(), client, qm, ranges, false)
+		require.NoError(t, err)
+		require.Len(t, sheetData.Grids, 4)
+		assert.Equal(t, "Archive", sheetData.Grids[0].Title)
+		assert.Empty(t, sheetData.Grids[0].NamedRange)
+		assert.Empty(t, sheetData.Grids[1].NamedRange)
+		assert.Equal(t, "KPI_Table", sheetData.Grids[2].NamedRange)
+		assert.Equal(t, "'Summary'!B5:B6", sheetData.Grids[2].ResolvedRange)
+		assert.Equal(t, "Other", sheetData.Grids[3].NamedRange)
+		assert.Equal(t, "'Summary'!B5:C8", sheetData.Grids[3].ResolvedRange)
+	})
+
 	t.Run("transformSheetToDataFrame", func(t *testing.T) {
 		sheet, err := loadTestSheet("./testdata/mixed-data.json")
 		require.NoError(t, err)
@@ -265,7 +771,7 @@ func TestGooglesheets(t *testing.T) {
 		qm := models.QueryModel{Range: "A1:O", Spreadsheet: "someId", CacheDurationSeconds: 10}
 
 		meta := make(map[string]any)
-		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], meta, "ref1", &qm)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], time.UTC, meta, "ref1", &qm)
 		require.NoError(t, err)
 		require.Equal(t, "ref1", frame.Name)
 
@@ -295,6 +801,96 @@ func TestGooglesheets(t *testing.T) {
 		})
 	})
 
+	t.Run("transformSheetsToDataFrames", func(t *testing.T) {
+		gsd := &GoogleSheets{}
+		qm := models.QueryModel{Ranges: []string{"North", "South"}, Spreadsheet: "someId"}
+		sheetData := &spreadsheetData{}
+		for _, sheet := range newTestSpreadsheet("North", "South").Sheets {
+			sheetData.Grids = append(sheetData.Grids, &sheetGrid{Title: sheet.Properties.Title, Data: sheet.Data[0]})
+		}
+
+		frames, err := gsd.transformSheetsToDataFrames(context.Background(), sheetData, time.UTC, map[string]any{"hit": false}, "ref1", &qm)
+		require.NoError(t, err)
+		require.Len(t, frames, 2)
+
+		for i, title := range []string{"North", "South"} {
+			assert.Equal(t, title, frames[i].Name)
+			assert.Equal(t, "ref1", frames[i].RefID)
+			assert.Equal(t, title, frames[i].Meta.Custom.(map[string]any)["sheet"])
+			assert.Equal(t, "'"+title+"'!A1:A2", frames[i].Meta.Custom.(map[string]any)["range"])
+		}
+	})
+
+	t.Run("getSheetDataCacheKey", func(t *testing.T) {
+		// The spreadsheet and the ranges can't run into each other
+		assert.NotEqual(t,
+			getSheetDataCacheKey(&models.QueryModel{Spreadsheet: "someId1"}, []string{"A1:B"}),
+			getSheetDataCacheKey(&models.QueryModel{Spreadsheet: "someId"}, []string{"1A1:B"}),
+		)
+		assert.NotEqual(t,
+			getSheetDataCacheKey(&models.QueryModel{Spreadsheet: "someId"}, []string{"'a,b'!A1"}),
+			getSheetDataCacheKey(&models.QueryModel{Spreadsheet: "someId"}, []string{"'a", "b'!A1"}),
+		)
+	})
+
+	t.Run("column type overrides", func(t *testing.T) {
+		one, three := 1.0, 3.0
+		notAvailable := "n/a"
+		gridData := &sheets.GridData{
+			StartRow: 4,
+			RowData: []*sheets.RowData{
+				{Values: []*sheets.CellData{{FormattedValue: "Value"}, {FormattedValue: "Active"}, {FormattedValue: "Date"}}},
+				{Values: []*sheets.CellData{{FormattedValue: "1", EffectiveValue: &sheets.ExtendedValue{NumberValue: &one}}, {FormattedValue: "TRUE"}, {FormattedValue: "18/10/2026"}}},
+				{Values: []*sheets.CellData{{FormattedValue: "n/a", EffectiveValue: &sheets.ExtendedValue{StringValue: &notAvailable}}, {FormattedValue: "no"}, {FormattedValue: "19/10/2026"}}},
+				{Values: []*sheets.CellData{{FormattedValue: "3", EffectiveValue: &sheets.ExtendedValue{NumberValue: &three}}, {FormattedValue: "false"}, {FormattedValue: "soon"}}},
+			},
+		}
+
+		gsd := &GoogleSheets{}
+		qm := models.QueryModel{
+			Spreadsheet: "someId",
+			ColumnTypes: map[string]string{"Value": "number", "Active": "boolean", "Date": "time"},
+			TimeFormat:  "02/01/2006",
+		}
+
+		meta := make(map[string]any)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), gridData, time.UTC, meta, "ref1", &qm)
+		require.NoError(t, err)
+
+		t.Run("cells are converted to the forced type", func(t *testing.T) {
+			assert.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[0].Type())
+			assert.Equal(t, 1.0, *frame.Fields[0].At(0).(*float64))
+			assert.Equal(t, 3.0, *frame.Fields[0].At(2).(*float64))
+
+			assert.Equal(t, data.FieldTypeNullableBool, frame.Fields[1].Type())
+			assert.True(t, *frame.Fields[1].At(0).(*bool))
+			assert.False(t, *frame.Fields[1].At(2).(*bool))
+
+			assert.Equal(t, data.FieldTypeNullableTime, frame.Fields[2].Type())
+			assert.Equal(t, 19, frame.Fields[2].At(1).(*time.Time).Day())
+		})
+
+		t.Run("failed conversions are nulls with a warning per cell", func(t *testing.T) {
+			assert.Nil(t, frame.Fields[0].At(1))
+			assert.Nil(t, frame.Fields[1].At(1))
+			assert.Nil(t, frame.Fields[2].At(2))
+
+			warnings, ok := meta["warnings"].([]string)
+			require.True(t, ok)
+			require.Len(t, warnings, 3)
+			assert.Contains(t, warnings[0], "Cell A7 in column \"Value\"")
+			assert.Contains(t, warnings[1], "Cell B7 in column \"Active\"")
+			assert.Contains(t, warnings[2], "Cell C8 in column \"Date\"")
+		})
+
+		t.Run("unknown type is an error", func(t *testing.T) {
+			qm := models.QueryModel{Spreadsheet: "someId", ColumnTypes: map[string]string{"Value": "currency"}}
+			_, err := gsd.transformSheetToDataFrame(context.Background(), gridData, time.UTC, make(map[string]any), "ref1", &qm)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		})
+	})
+
 	t.Run("query single cell", func(t *testing.T) {
 		sheet, err := loadTestSheet("./testdata/single-cell.json")
 		require.NoError(t, err)
@@ -305,7 +901,7 @@ func TestGooglesheets(t *testing.T) {
 		qm := models.QueryModel{Range: "A2", Spreadsheet: "someId", CacheDurationSeconds: 10}
 
 		meta := make(map[string]any)
-		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], meta, "ref1", &qm)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), sheet.Sheets[0].Data[0], time.UTC, meta, "ref1", &qm)
 		require.NoError(t, err)
 		require.Equal(t, "ref1", frame.Name)
 
@@ -327,6 +923,32 @@ func TestGooglesheets(t *testing.T) {
 		})
 	})
 
+	t.Run("getLocation", func(t *testing.T) {
+		t.Run("query timezone overrides the spreadsheet timezone", func(t *testing.T) {
+			loc, err := getLocation(context.Background(), &models.QueryModel{Timezone: "America/New_York"}, "Europe/Berlin")
+			require.NoError(t, err)
+			assert.Equal(t, "America/New_York", loc.String())
+		})
+
+		t.Run("spreadsheet timezone is used by default", func(t *testing.T) {
+			loc, err := getLocation(context.Background(), &models.QueryModel{}, "Europe/Berlin")
+			require.NoError(t, err)
+			assert.Equal(t, "Europe/Berlin", loc.String())
+		})
+
+		t.Run("UTC is used without a valid spreadsheet timezone", func(t *testing.T) {
+			loc, err := getLocation(context.Background(), &models.QueryModel{}, "Nowhere/Special")
+			require.NoError(t, err)
+			assert.Equal(t, time.UTC, loc)
+		})
+
+		t.Run("invalid query timezone is a downstream error", func(t *testing.T) {
+			_, err := getLocation(context.Background(), &models.QueryModel{Timezone: "Nowhere/Special"}, "Europe/Berlin")
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		})
+	})
+
 	t.Run("column id formatting", func(t *testing.T) {
 		require.Equal(t, "A", getExcelColumnName(1))
 		require.Equal(t, "B", getExcelColumnName(2))
@@ -360,7 +982,7 @@ func TestGooglesheets(t *testing.T) {
 		gsd := &GoogleSheets{Cache: cache.New(300*time.Second, 50*time.Second)}
 		qm := models.QueryModel{Range: "A1:A2", Spreadsheet: "test", CacheDurationSeconds: 10}
 
-		frame, err := gsd.transformSheetToDataFrame(context.Background(), gridData, make(map[string]any), "ref1", &qm)
+		frame, err := gsd.transformSheetToDataFrame(context.Background(), gridData, time.UTC, make(map[string]any), "ref1", &qm)
 		require.NoError(t, err)
 
 		assert.Equal(t, data.FieldTypeNullableString, frame.Fields[0].Type())
@@ -380,7 +1002,7 @@ func Test_timeConverter(t *testing.T) {
 			},
 		}
 
-		result, err := timeConverter.Converter(cell)
+		result, err := newTimeConverter(time.UTC, "").Converter(cell)
 		require.NoError(t, err)
 
 		require.NotNil(t, result)
@@ -394,12 +1016,31 @@ func Test_timeConverter(t *testing.T) {
 		assert.Equal(t, 0, date.Second())
 	})
 
+	t.Run("timeConverter reads Number Value in the given location", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		serialDate := 46082.375 // 1 March 2026 09:00:00
+		cell := &sheets.CellData{
+			EffectiveValue: &sheets.ExtendedValue{
+				NumberValue: &serialDate,
+			},
+		}
+
+		result, err := newTimeConverter(loc, "").Converter(cell)
+		require.NoError(t, err)
+
+		date, ok := result.(*time.Time)
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC), date.UTC())
+		assert.Equal(t, 9, date.Hour())
+	})
+
 	t.Run("timeConverter without Number Value falls back to parsing FormattedValue", func(t *testing.T) {
 		cell := &sheets.CellData{
 			FormattedValue: "2020-01-15 12:00:00",
 		}
 
-		result, err := timeConverter.Converter(cell)
+		result, err := newTimeConverter(time.UTC, "").Converter(cell)
 		require.NoError(t, err)
 
 		require.NotNil(t, result)
@@ -413,15 +1054,63 @@ func Test_timeConverter(t *testing.T) {
 		assert.Equal(t, 0, date.Second())
 	})
 
+	t.Run("timeConverter parses FormattedValue in the given location", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		cell := &sheets.CellData{
+			FormattedValue: "2020-01-15 12:00:00",
+		}
+
+		result, err := newTimeConverter(loc, "").Converter(cell)
+		require.NoError(t, err)
+
+		date, ok := result.(*time.Time)
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2020, time.January, 15, 11, 0, 0, 0, time.UTC), date.UTC())
+	})
+
 	t.Run("timeConverter returns error when parsing FormattedValue fails", func(t *testing.T) {
 		cell := &sheets.CellData{
 			FormattedValue: "not a valid date",
 		}
 
-		_, err := timeConverter.Converter(cell)
+		_, err := newTimeConverter(time.UTC, "").Converter(cell)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "error while parsing date")
 		assert.Contains(t, err.Error(), "not a valid date")
 	})
 }
+
+func Test_serialNumberToTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		serial   float64
+		loc      *time.Location
+		expected time.Time
+	}{
+		{name: "UTC", serial: 43845.5, loc: time.UTC, expected: time.Date(2020, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "winter time", serial: 46082.375, loc: berlin, expected: time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)},
+		{name: "date only is local midnight", serial: 46082, loc: berlin, expected: time.Date(2026, time.February, 28, 23, 0, 0, 0, time.UTC)},
+		{name: "day of the switch to summer time", serial: 46110.5, loc: berlin, expected: time.Date(2026, time.March, 29, 10, 0, 0, 0, time.UTC)},
+		{name: "date only on the day of the switch to summer time", serial: 46110, loc: berlin, expected: time.Date(2026, time.March, 28, 23, 0, 0, 0, time.UTC)},
+		{name: "after the switch to summer time", serial: 46110.125, loc: berlin, expected: time.Date(2026, time.March, 29, 1, 0, 0, 0, time.UTC)},
+		{name: "day of the switch to winter time", serial: 46320.5, loc: berlin, expected: time.Date(2026, time.October, 25, 11, 0, 0, 0, time.UTC)},
+		{name: "west of UTC", serial: 46082.375, loc: newYork, expected: time.Date(2026, time.March, 1, 14, 0, 0, 0, time.UTC)},
+		{name: "floating point error is rounded", serial: 46082 + 9.5/24, loc: time.UTC, expected: time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "time only", serial: 0.75, loc: time.UTC, expected: time.Date(1899, time.December, 30, 18, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := serialNumberToTime(tt.serial, tt.loc)
+			assert.True(t, tt.expected.Equal(result), "expected %s, got %s", tt.expected, result.UTC())
+			assert.Equal(t, tt.loc, result.Location())
+		})
+	}
+}
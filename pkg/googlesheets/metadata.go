@@ -0,0 +1,197 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"google.golang.org/api/drive/v3"
+)
+
+// MetadataQuery returns the spreadsheets, the sheets or the Drive file metadata of a spreadsheet
+// as a frame, depending on the query type.
+func (gs *GoogleSheets) MetadataQuery(ctx context.Context, refID string, qm *models.QueryModel, config models.DatasourceSettings) backend.DataResponse {
+	client, err := NewGoogleClient(ctx, config)
+	if err != nil {
+		dr := backend.ErrorResponseWithErrorSource(err)
+		dr.Error = fmt.Errorf("unable to create Google API client: %w", err)
+		return dr
+	}
+
+	var frame *data.Frame
+	switch qm.QueryType {
+	case models.QueryTypeSpreadsheets:
+		var files []*drive.File
+		files, err = getAllSpreadsheetFiles(ctx, client, newSpreadsheetFilesQuery(config, "", ""))
+		if err != nil {
+			err = handleGoogleAPIError(ctx, err)
+			break
+		}
+		frame = newSpreadsheetsFrame(files)
+	case models.QueryTypeSheets:
+		frame, err = gs.getSheetsFrame(ctx, client, qm.Spreadsheet)
+	case models.QueryTypeFileInfo:
+		frame, err = getFileInfoFrame(ctx, client, qm.Spreadsheet)
+	default:
+		err = backend.DownstreamError(fmt.Errorf("unknown query type %q", qm.QueryType))
+	}
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	frame.Name = refID
+	frame.RefID = refID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// getAllSpreadsheetFiles lists the spreadsheet files of all the pages of the query.
+func getAllSpreadsheetFiles(ctx context.Context, client *GoogleClient, query SpreadsheetFilesQuery) ([]*drive.File, error) {
+	files := []*drive.File{}
+	for {
+		fileList, err := client.GetSpreadsheetFiles(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, fileList.Files...)
+		query.PageToken = fileList.NextPageToken
+		if query.PageToken == "" {
+			return files, nil
+		}
+	}
+}
+
+// newSpreadsheetsFrame returns a frame with a row for each spreadsheet file.
+func newSpreadsheetsFrame(files []*drive.File) *data.Frame {
+	frame := data.NewFrame("",
+		data.NewField("id", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("modifiedTime", nil, []*time.Time{}),
+		data.NewField("webViewLink", nil, []string{}),
+	)
+	for _, file := range files {
+		owner := ""
+		if len(file.Owners) > 0 {
+			owner = getDriveUserName(file.Owners[0])
+		}
+		frame.AppendRow(file.Id, file.Name, owner, parseDriveTime(file.ModifiedTime), file.WebViewLink)
+	}
+	return frame
+}
+
+// getSheetsFrame returns a frame with a row for each sheet of the spreadsheet.
+func (gs *GoogleSheets) getSheetsFrame(ctx context.Context, client client, spreadsheetID string) (*data.Frame, error) {
+	if spreadsheetID == "" {
+		return nil, backend.DownstreamError(errors.New("missing spreadsheet"))
+	}
+	sheetInfos, err := gs.getSheets(ctx, client, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame("",
+		data.NewField("title", nil, []string{}),
+		data.NewField("sheetId", nil, []int64{}),
+		data.NewField("index", nil, []int64{}),
+		data.NewField("hidden", nil, []bool{}),
+		data.NewField("rowCount", nil, []int64{}),
+		data.NewField("columnCount", nil, []int64{}),
+	)
+	for _, info := range sheetInfos {
+		frame.AppendRow(info.Title, info.SheetID, info.Index, info.Hidden, info.RowCount, info.ColumnCount)
+	}
+	return frame, nil
+}
+
+// getFileInfoFrame returns a single row frame with the Drive file metadata of the spreadsheet.
+func getFileInfoFrame(ctx context.Context, client client, spreadsheetID string) (*data.Frame, error) {
+	if spreadsheetID == "" {
+		return nil, backend.DownstreamError(errors.New("missing spreadsheet"))
+	}
+	file, err := client.GetFile(ctx, spreadsheetID)
+	if err != nil {
+		return nil, handleGoogleAPIError(ctx, err)
+	}
+	return newFileInfoFrame(file), nil
+}
+
+// newFileInfoFrame returns a single row frame with the Drive file metadata.
+func newFileInfoFrame(file *drive.File) *data.Frame {
+	owners := make([]string, 0, len(file.Owners))
+	for _, owner := range file.Owners {
+		owners = append(owners, getDriveUserName(owner))
+	}
+
+	// Native Google files don't have a size
+	var size *int64
+	if file.Size > 0 {
+		size = &file.Size
+	}
+
+	return data.NewFrame("",
+		data.NewField("id", nil, []string{file.Id}),
+		data.NewField("name", nil, []string{file.Name}),
+		data.NewField("mimeType", nil, []string{file.MimeType}),
+		data.NewField("createdTime", nil, []*time.Time{parseDriveTime(file.CreatedTime)}),
+		data.NewField("modifiedTime", nil, []*time.Time{parseDriveTime(file.ModifiedTime)}),
+		data.NewField("owners", nil, []string{strings.Join(owners, ", ")}),
+		data.NewField("size", nil, []*int64{size}).SetConfig(&data.FieldConfig{Unit: "bytes"}),
+		data.NewField("webViewLink", nil, []string{file.WebViewLink}),
+		data.NewField("lastModifyingUser", nil, []string{getDriveUserName(file.LastModifyingUser)}),
+		data.NewField("version", nil, []int64{file.Version}),
+	)
+}
+
+// getFreshnessMeta returns the modified time, last modifying user and version of a Drive file, as set in the frame meta.
+func getFreshnessMeta(file *drive.File) map[string]any {
+	return map[string]any{
+		"modifiedTime":      file.ModifiedTime,
+		"lastModifyingUser": getDriveUserName(file.LastModifyingUser),
+		"version":           file.Version,
+	}
+}
+
+// newFreshnessFrame returns a single row frame with the modified time, last modifying user and version of
+// a Drive file, along with the age of the last change at the given time, such as to alert on stale spreadsheets.
+func newFreshnessFrame(file *drive.File, now time.Time) *data.Frame {
+	modifiedTime := parseDriveTime(file.ModifiedTime)
+	var age *float64
+	if modifiedTime != nil {
+		seconds := now.Sub(*modifiedTime).Seconds()
+		age = &seconds
+	}
+
+	return data.NewFrame("freshness",
+		data.NewField("modifiedTime", nil, []*time.Time{modifiedTime}),
+		data.NewField("age", nil, []*float64{age}).SetConfig(&data.FieldConfig{Unit: "s"}),
+		data.NewField("lastModifyingUser", nil, []string{getDriveUserName(file.LastModifyingUser)}),
+		data.NewField("version", nil, []int64{file.Version}),
+	)
+}
+
+// getDriveUserName returns the display name of a Drive user, else its email address.
+func getDriveUserName(user *drive.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.EmailAddress
+}
+
+// parseDriveTime parses a time returned by the Drive API. It returns nil for missing or invalid times.
+func parseDriveTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,81 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestMetadataFrames(t *testing.T) {
+	t.Run("spreadsheets frame", func(t *testing.T) {
+		frame := newSpreadsheetsFrame([]*drive.File{{Id: "1", Name: "Budget"}, {Id: "2", Name: "Inventory"}})
+		require.Equal(t, 2, frame.Rows())
+		assert.Equal(t, "2", frame.Fields[0].At(1))
+		assert.Equal(t, "Inventory", frame.Fields[1].At(1))
+	})
+
+	t.Run("sheets frame", func(t *testing.T) {
+		client := &fakeClient{}
+		gsd := &GoogleSheets{
+			Cache: cache.New(300*time.Second, 50*time.Second),
+		}
+		spreadsheet := newTestSpreadsheet("Summary", "Archive")
+		spreadsheet.Sheets[1].Properties.SheetId = 42
+		client.On("GetSpreadsheet", context.Background(), "someId", []string(nil), false).Return(spreadsheet, nil)
+
+		frame, err := gsd.getSheetsFrame(context.Background(), client, "someId")
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		assert.Equal(t, "Archive", frame.Fields[0].At(1))
+		assert.Equal(t, int64(42), frame.Fields[1].At(1))
+	})
+
+	t.Run("file info frame", func(t *testing.T) {
+		client := &fakeClient{}
+		client.On("GetFile", context.Background(), "someId").Return(&drive.File{
+			Id:           "someId",
+			Name:         "Budget",
+			ModifiedTime: "2026-03-01T10:00:00.000Z",
+			Owners:       []*drive.User{{DisplayName: "Alex"}, {EmailAddress: "sam@example.com"}},
+		}, nil)
+
+		frame, err := getFileInfoFrame(context.Background(), client, "someId")
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+
+		modifiedTime, _ := frame.FieldByName("modifiedTime")
+		assert.Equal(t, time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC), *modifiedTime.At(0).(*time.Time))
+		createdTime, _ := frame.FieldByName("createdTime")
+		assert.Nil(t, createdTime.At(0))
+		owners, _ := frame.FieldByName("owners")
+		assert.Equal(t, "Alex, sam@example.com", owners.At(0))
+		size, _ := frame.FieldByName("size")
+		assert.Nil(t, size.At(0))
+	})
+
+	t.Run("freshness", func(t *testing.T) {
+		file := &drive.File{
+			ModifiedTime:      "2026-03-01T10:00:00Z",
+			LastModifyingUser: &drive.User{DisplayName: "Alex", EmailAddress: "alex@example.com"},
+			Version:           12,
+		}
+
+		assert.Equal(t, map[string]any{
+			"modifiedTime":      "2026-03-01T10:00:00Z",
+			"lastModifyingUser": "Alex",
+			"version":           int64(12),
+		}, getFreshnessMeta(file))
+
+		frame := newFreshnessFrame(file, time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC))
+		require.Equal(t, 1, frame.Rows())
+		age, _ := frame.FieldByName("age")
+		assert.Equal(t, (48 * time.Hour).Seconds(), *age.At(0).(*float64))
+		version, _ := frame.FieldByName("version")
+		assert.Equal(t, int64(12), version.At(0))
+	})
+}
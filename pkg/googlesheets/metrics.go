@@ -0,0 +1,42 @@
+package googlesheets
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// coalescedFetches counts the queries served by the fetch of another concurrent query instead of calling the API.
+var coalescedFetches = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "grafana_plugin",
+	Subsystem: "googlesheets",
+	Name:      "coalesced_fetches_total",
+	Help:      "Number of spreadsheet fetches shared with a concurrent identical fetch instead of calling the Google API.",
+})
+
+// The metrics of the LRU cache, summed over the datasource instances using it.
+var (
+	cacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana_plugin",
+		Subsystem: "googlesheets",
+		Name:      "cache_hits_total",
+		Help:      "Number of lookups finding an unexpired value in the LRU cache.",
+	})
+	cacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana_plugin",
+		Subsystem: "googlesheets",
+		Name:      "cache_misses_total",
+		Help:      "Number of lookups finding no value or an expired value in the LRU cache.",
+	})
+	cacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana_plugin",
+		Subsystem: "googlesheets",
+		Name:      "cache_evictions_total",
+		Help:      "Number of values evicted from the LRU cache, or not stored, to stay within its byte budget.",
+	})
+	cacheBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "grafana_plugin",
+		Subsystem: "googlesheets",
+		Name:      "cache_bytes",
+		Help:      "Estimated bytes of the values held by the LRU cache.",
+	})
+)
@@ -24,7 +24,8 @@ func RoundTripper(_ httpclient.Options, next http.RoundTripper) http.RoundTrippe
 		}
 
 		res.Body = httpclient.CountBytesReader(res.Body, func(size int64) {
-			backend.Logger.FromContext(req.Context()).Debug("Downstream response info", "bytes", size, "url", req.URL.String(), "retrieved", true)
+			// The fields mask tells how much of the resource was requested
+			backend.Logger.FromContext(req.Context()).Debug("Downstream response info", "bytes", size, "url", req.URL.String(), "fields", req.URL.Query().Get("fields"), "retrieved", true)
 		})
 		return res, err
 	})
@@ -0,0 +1,72 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+)
+
+const (
+	// schemaSampleCount is the number of sample values returned for each column of a schema.
+	schemaSampleCount = 5
+	// schemaCacheDurationSeconds is how long the data read for a schema is cached, as the query editor asks
+	// for the schema of a range again on each change of the query.
+	schemaCacheDurationSeconds = 60
+)
+
+// ColumnSchema describes how a column of a range is interpreted by the queries.
+type ColumnSchema struct {
+	Header     string     `json:"header"`
+	Type       ColumnType `json:"type"`
+	Unit       string     `json:"unit"`
+	MixedTypes bool       `json:"mixedTypes"`
+	MixedUnits bool       `json:"mixedUnits"`
+	Samples    []string   `json:"samples"`
+}
+
+// GetSchema gets the columns of a spreadsheet range from the Google API, as a query would read them.
+func (gs *GoogleSheets) GetSchema(ctx context.Context, config models.DatasourceSettings, qm *models.QueryModel) ([]ColumnSchema, error) {
+	client, err := NewGoogleClient(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google API client: %w", err)
+	}
+
+	return gs.getSchema(ctx, client, qm)
+}
+
+// getSchema gets the columns of the first range of the query, with a few sample values for each of them.
+func (gs *GoogleSheets) getSchema(ctx context.Context, client client, qm *models.QueryModel) ([]ColumnSchema, error) {
+	// All the rows are read, as they all make the type of a column, but they are cached like those of a query
+	cached := *qm
+	if cached.CacheDurationSeconds <= 0 {
+		cached.CacheDurationSeconds = schemaCacheDurationSeconds
+	}
+	sheetData, _, err := gs.getSheetData(ctx, client, &cached)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := sheetData.Grids[0].Data.RowData
+	columns, start := getColumnDefinitions(rows, qm)
+	schema := make([]ColumnSchema, 0, len(columns))
+	for _, column := range columns {
+		samples := []string{}
+		for rowIndex := start; rowIndex < len(rows) && len(samples) < schemaSampleCount; rowIndex++ {
+			values := rows[rowIndex].Values
+			if column.ColumnIndex < len(values) && values[column.ColumnIndex] != nil && values[column.ColumnIndex].FormattedValue != "" {
+				samples = append(samples, values[column.ColumnIndex].FormattedValue)
+			}
+		}
+
+		schema = append(schema, ColumnSchema{
+			Header:     column.Header,
+			Type:       column.GetType(),
+			Unit:       column.GetUnit(),
+			MixedTypes: column.HasMixedTypes(),
+			MixedUnits: column.HasMixedUnits(),
+			Samples:    samples,
+		})
+	}
+	return schema, nil
+}
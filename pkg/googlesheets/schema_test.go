@@ -0,0 +1,61 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestGetSchema(t *testing.T) {
+	client := &fakeClient{}
+	gsd := &GoogleSheets{
+		Cache: cache.New(300*time.Second, 50*time.Second),
+	}
+	qm := &models.QueryModel{Spreadsheet: "someId", Range: "Sheet1!A1:C"}
+
+	number := func(value float64, formattedValue string) *sheets.CellData {
+		return &sheets.CellData{FormattedValue: formattedValue, EffectiveValue: &sheets.ExtendedValue{NumberValue: &value}}
+	}
+	grid := newTestGrid([]string{"Region", "Amount", "Mixed"})
+	for i, region := range []string{"North", "South", "East", "West", "Center", "Islands"} {
+		value := region
+		grid.RowData = append(grid.RowData, &sheets.RowData{Values: []*sheets.CellData{
+			{FormattedValue: region, EffectiveValue: &sheets.ExtendedValue{StringValue: &value}},
+			number(float64(i+1), "1"),
+		}})
+	}
+	grid.RowData[1].Values = append(grid.RowData[1].Values, number(5, "5"))
+	grid.RowData[2].Values = append(grid.RowData[2].Values, &sheets.CellData{FormattedValue: "n/a"})
+
+	spreadsheet := &sheets.Spreadsheet{Sheets: []*sheets.Sheet{{Properties: &sheets.SheetProperties{Title: "Sheet1"}, Data: []*sheets.GridData{grid}}}}
+	client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Sheet1!A1:C"}, true).Return(spreadsheet, nil).Once()
+
+	schema, err := gsd.getSchema(context.Background(), client, qm)
+	require.NoError(t, err)
+	require.Len(t, schema, 3)
+
+	assert.Equal(t, ColumnSchema{
+		Header:  "Region",
+		Type:    ColumTypeString,
+		Samples: []string{"North", "South", "East", "West", "Center"},
+	}, schema[0])
+	assert.Equal(t, ColumnType(ColumTypeNumber), schema[1].Type)
+	assert.False(t, schema[1].MixedTypes)
+	assert.Equal(t, ColumnType(ColumTypeString), schema[2].Type)
+	assert.True(t, schema[2].MixedTypes)
+	assert.Equal(t, []string{"5", "n/a"}, schema[2].Samples)
+
+	// The range is read once while cached
+	cached, err := gsd.getSchema(context.Background(), client, qm)
+	require.NoError(t, err)
+	assert.Equal(t, schema, cached)
+	client.AssertExpectations(t)
+}
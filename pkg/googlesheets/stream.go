@@ -0,0 +1,132 @@
+package googlesheets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// defaultStreamInterval is how often a stream polls its spreadsheet by default.
+	defaultStreamInterval = 30 * time.Second
+	// minStreamInterval keeps streams from exhausting the Sheets API quota.
+	minStreamInterval = 5 * time.Second
+)
+
+// getStreamQueryModel reads the query of a stream. The path of the stream channel is the spreadsheet followed by
+// the key of the query, which must match the query so that each query gets its own channel.
+func getStreamQueryModel(path string, queryJSON json.RawMessage) (*models.QueryModel, error) {
+	spreadsheetID, key, ok := strings.Cut(path, "/")
+	if !ok || spreadsheetID == "" || key == "" {
+		return nil, fmt.Errorf("invalid stream path %q", path)
+	}
+	qm, err := readStreamQueryModel(queryJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := getStreamPath(qm)
+	if err != nil {
+		return nil, err
+	}
+	if path != expected {
+		return nil, fmt.Errorf("stream path %q doesn't match the query", path)
+	}
+	return qm, nil
+}
+
+// readStreamQueryModel reads the query of a stream, normalized for polling.
+func readStreamQueryModel(queryJSON json.RawMessage) (*models.QueryModel, error) {
+	if len(queryJSON) == 0 {
+		return nil, errors.New("missing stream query")
+	}
+	qm, err := models.GetQueryModel(backend.DataQuery{JSON: queryJSON})
+	if err != nil {
+		return nil, err
+	}
+	if qm.Spreadsheet == "" {
+		return nil, errors.New("missing stream spreadsheet")
+	}
+
+	// Streams have no time range, and every poll gets the latest data. The freshness frame is left out, as its
+	// age changes at each poll and the frames are only sent when they change.
+	qm.UseTimeFilter = false
+	qm.CacheDurationSeconds = 0
+	qm.FreshnessFrame = false
+	return qm, nil
+}
+
+// getStreamPath returns the channel path of the stream of a query: its spreadsheet followed by a hash of the
+// normalized query, so that the panels running the same query share a channel.
+func getStreamPath(qm *models.QueryModel) (string, error) {
+	queryJSON, err := json.Marshal(qm)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(queryJSON)
+	return qm.Spreadsheet + "/" + hex.EncodeToString(hash[:]), nil
+}
+
+// getStreamInterval returns how often the stream of a query polls its spreadsheet.
+func getStreamInterval(qm *models.QueryModel) time.Duration {
+	if qm.StreamIntervalSeconds <= 0 {
+		return defaultStreamInterval
+	}
+	return max(time.Duration(qm.StreamIntervalSeconds)*time.Second, minStreamInterval)
+}
+
+// runStream queries the spreadsheet at each interval, and sends the frames whose content changed since
+// the previous query. Failed queries are logged and retried at the next interval.
+func (gs *GoogleSheets) runStream(ctx context.Context, client client, qm *models.QueryModel, interval time.Duration, send func(*data.Frame) error) error {
+	logger := backend.Logger.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Hashes of the frames last sent, by frame index
+	hashes := map[int]string{}
+	for {
+		dr := gs.query(ctx, client, "", qm, backend.TimeRange{})
+		if dr.Error != nil {
+			logger.Warn("Stream query failed", "spreadsheet", qm.Spreadsheet, "error", dr.Error)
+		}
+		for i, frame := range dr.Frames {
+			hash, err := hashFrame(frame)
+			if err != nil {
+				return err
+			}
+			if hashes[i] == hash {
+				continue
+			}
+			if err := send(frame); err != nil {
+				return err
+			}
+			hashes[i] = hash
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// hashFrame returns a hash of the fields, values and meta of the frame.
+func hashFrame(frame *data.Frame) (string, error) {
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(frameJSON)
+	return hex.EncodeToString(hash[:]), nil
+}
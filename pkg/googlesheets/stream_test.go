@@ -0,0 +1,73 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStreamQueryModel(t *testing.T) {
+	queryJSON := []byte(`{"spreadsheet":"someId","range":"Status!A1:C","cacheDurationSeconds":300,"useTimeFilter":true,"freshnessFrame":true}`)
+	path, err := getStreamPath(&models.QueryModel{Spreadsheet: "someId", Range: "Status!A1:C"})
+	require.NoError(t, err)
+
+	qm, err := getStreamQueryModel(path, queryJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "Status!A1:C", qm.Range)
+	assert.Equal(t, 0, qm.CacheDurationSeconds)
+	assert.False(t, qm.UseTimeFilter)
+	assert.False(t, qm.FreshnessFrame)
+
+	for path, query := range map[string]string{
+		"someId":        `{"spreadsheet":"someId"}`,
+		path:            ``,
+		"someId/abc123": string(queryJSON),
+		// The path of another query of the spreadsheet
+		path + "0": string(queryJSON),
+		"":         `{"spreadsheet":""}`,
+	} {
+		_, err := getStreamQueryModel(path, []byte(query))
+		assert.Error(t, err, path)
+	}
+}
+
+func TestGetStreamInterval(t *testing.T) {
+	assert.Equal(t, defaultStreamInterval, getStreamInterval(&models.QueryModel{}))
+	assert.Equal(t, minStreamInterval, getStreamInterval(&models.QueryModel{StreamIntervalSeconds: 1}))
+	assert.Equal(t, time.Minute, getStreamInterval(&models.QueryModel{StreamIntervalSeconds: 60}))
+}
+
+func TestRunStream(t *testing.T) {
+	client := &fakeClient{}
+	gsd := &GoogleSheets{
+		Cache: cache.New(300*time.Second, 50*time.Second),
+	}
+	qm := &models.QueryModel{Spreadsheet: "someId", Range: "Status!A1:A"}
+
+	// The frame is only sent again once the sheet changes
+	unchanged := newTestSpreadsheet("Status")
+	client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Status!A1:A"}, true).Return(unchanged, nil).Twice()
+	client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Status!A1:A"}, true).Return(newTestSpreadsheet("Changed"), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sent := []string{}
+	err := gsd.runStream(ctx, client, qm, time.Millisecond, func(frame *data.Frame) error {
+		sent = append(sent, *frame.Fields[0].At(0).(*string))
+		if len(sent) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Status", "Changed"}, sent)
+	client.AssertNumberOfCalls(t, "GetSpreadsheet", 3)
+}
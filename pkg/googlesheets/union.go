@@ -0,0 +1,140 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"google.golang.org/api/sheets/v4"
+)
+
+// unionSheetColumnName is the name of the column holding the sheet each row comes from.
+const unionSheetColumnName = "Sheet"
+
+// getUnionRanges returns a range for each sheet whose title matches the union pattern of the query.
+func getUnionRanges(ctx context.Context, client client, qm *models.QueryModel) ([]string, error) {
+	if len(qm.Ranges) > 0 {
+		return nil, backend.DownstreamError(errors.New("a sheet pattern can't be combined with multiple ranges, use a single range"))
+	}
+	pattern, err := regexp.Compile(qm.UnionSheets)
+	if err != nil {
+		return nil, backend.DownstreamError(fmt.Errorf("invalid sheet pattern %q: %w", qm.UnionSheets, err))
+	}
+
+	// Only the sheet properties are needed to find the matching sheets
+	result, err := client.GetSpreadsheet(ctx, qm.Spreadsheet, nil, false)
+	if err != nil {
+		return nil, handleGoogleAPIError(ctx, err)
+	}
+
+	// The range may refer to a sheet, only the cells are applied to the matching sheets
+	cells := qm.Range
+	if i := strings.LastIndex(cells, "!"); i >= 0 {
+		cells = cells[i+1:]
+	}
+
+	ranges := []string{}
+	for _, sheet := range result.Sheets {
+		if sheet.Properties == nil || !pattern.MatchString(sheet.Properties.Title) {
+			continue
+		}
+		sheetRange := quoteSheetTitle(sheet.Properties.Title)
+		if cells != "" {
+			sheetRange += "!" + cells
+		}
+		ranges = append(ranges, sheetRange)
+	}
+	if len(ranges) == 0 {
+		return nil, backend.DownstreamError(fmt.Errorf("no sheet matches the pattern %q", qm.UnionSheets))
+	}
+
+	return ranges, nil
+}
+
+// unionRowSource is the sheet a stacked row comes from, with its zero-based row and start column in the sheet.
+type unionRowSource struct {
+	title       string
+	row         int
+	startColumn int
+}
+
+// transformUnionToDataFrame stacks the grids of the sheet data into a single data frame,
+// with an additional column holding the title of the sheet each row comes from.
+// Grids with columns that differ from those of the first grid are skipped and reported as notices.
+func (gs *GoogleSheets) transformUnionToDataFrame(ctx context.Context, sheetData *spreadsheetData, loc *time.Location, meta map[string]any, refID string, qm *models.QueryModel) (*data.Frame, error) {
+	var (
+		headers    []string
+		headerRows []*sheets.RowData
+		rows       []*sheets.RowData
+		titles     []string
+		// sources holds the cell of each row in its sheet, for the warnings
+		sources []unionRowSource
+		notices []data.Notice
+	)
+	first, count := getHeaderRows(qm)
+	for _, grid := range sheetData.Grids {
+		if !qm.NoHeader && len(grid.Data.RowData) <= first+count {
+			notices = append(notices, data.Notice{
+				Severity: data.NoticeSeverityInfo,
+				Text:     fmt.Sprintf("Sheet %q has no data rows", grid.Title),
+			})
+			continue
+		}
+
+		names, start := getColumnHeaders(grid.Data.RowData, qm)
+		if headers == nil {
+			headers = names
+			headerRows = grid.Data.RowData[:start]
+		} else if !slices.Equal(headers, names) {
+			notices = append(notices, data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("Sheet %q was skipped because its columns don't match those of sheet %q", grid.Title, titles[0]),
+			})
+			continue
+		}
+
+		for i, row := range grid.Data.RowData[start:] {
+			rows = append(rows, row)
+			titles = append(titles, grid.Title)
+			sources = append(sources, unionRowSource{
+				title:       grid.Title,
+				row:         int(grid.Data.StartRow) + start + i,
+				startColumn: int(grid.Data.StartColumn),
+			})
+		}
+	}
+	if headers == nil {
+		return nil, backend.DownstreamError(errors.New("none of the matching sheets has data rows"))
+	}
+
+	union := &sheets.GridData{RowData: append(slices.Clone(headerRows), rows...)}
+	frame, err := gs.transformGridToDataFrame(ctx, union, loc, meta, refID, qm, func(rowIndex, columnIndex int) string {
+		source := sources[rowIndex-len(headerRows)]
+		return quoteSheetTitle(source.title) + "!" + getExcelColumnName(source.startColumn+columnIndex+1) + strconv.Itoa(source.row+1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	columnMap := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		columnMap[header] = true
+	}
+	name := getUniqueColumnName(unionSheetColumnName, len(headers), columnMap)
+	field := data.NewField(name, nil, titles)
+	field.Config = &data.FieldConfig{DisplayName: name}
+	frame.Fields = append(frame.Fields, field)
+
+	meta["sheets"] = slices.Compact(slices.Clone(titles))
+	frame.Meta.Notices = append(frame.Meta.Notices, notices...)
+	return frame, nil
+}
@@ -0,0 +1,126 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnion(t *testing.T) {
+	t.Run("getUnionRanges", func(t *testing.T) {
+		t.Run("returns a range for each matching sheet", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := &models.QueryModel{Spreadsheet: "someId", Range: "Template!A1:C", UnionSheets: `^\d{4}-\d{2}$`}
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, []string(nil), false).Return(newTestSpreadsheet("2026-01", "Summary", "2026-02", "It's 2026-03"), nil)
+
+			ranges, err := getUnionRanges(context.Background(), client, qm)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"'2026-01'!A1:C", "'2026-02'!A1:C"}, ranges)
+			client.AssertExpectations(t)
+		})
+
+		t.Run("quotes sheet titles", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := &models.QueryModel{Spreadsheet: "someId", UnionSheets: "2026"}
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, []string(nil), false).Return(newTestSpreadsheet("It's 2026"), nil)
+
+			ranges, err := getUnionRanges(context.Background(), client, qm)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"'It''s 2026'"}, ranges)
+		})
+
+		t.Run("returns a downstream error when no sheet matches", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := &models.QueryModel{Spreadsheet: "someId", UnionSheets: "^2027"}
+			client.On("GetSpreadsheet", mock.Anything, qm.Spreadsheet, []string(nil), false).Return(newTestSpreadsheet("2026-01"), nil)
+
+			_, err := getUnionRanges(context.Background(), client, qm)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+		})
+
+		t.Run("returns a downstream error with multiple ranges", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := &models.QueryModel{Spreadsheet: "someId", Ranges: []string{"A1:B", "D1:E"}, UnionSheets: "2026"}
+
+			_, err := getUnionRanges(context.Background(), client, qm)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+			client.AssertNotCalled(t, "GetSpreadsheet")
+		})
+
+		t.Run("returns a downstream error for an invalid pattern", func(t *testing.T) {
+			client := &fakeClient{}
+			qm := &models.QueryModel{Spreadsheet: "someId", UnionSheets: "("}
+
+			_, err := getUnionRanges(context.Background(), client, qm)
+			require.Error(t, err)
+			assert.True(t, backend.IsDownstreamError(err))
+			client.AssertNotCalled(t, "GetSpreadsheet")
+		})
+	})
+
+	t.Run("transformUnionToDataFrame", func(t *testing.T) {
+		gsd := &GoogleSheets{}
+		qm := &models.QueryModel{Spreadsheet: "someId", UnionSheets: "2026"}
+		sheetData := &spreadsheetData{Grids: []*sheetGrid{
+			{Title: "2026-01", Data: newTestGrid([]string{"Item", "Amount"}, []string{"Rent", "1000"}, []string{"Food", "250"})},
+			{Title: "2026-02", Data: newTestGrid([]string{"Item", "Amount"}, []string{"Rent", "1000"})},
+			{Title: "2026-03", Data: newTestGrid([]string{"Item", "Cost"}, []string{"Rent", "1100"})},
+			{Title: "2026-04", Data: newTestGrid([]string{"Item", "Amount"})},
+		}}
+
+		meta := map[string]any{}
+		frame, err := gsd.transformUnionToDataFrame(context.Background(), sheetData, time.UTC, meta, "ref1", qm)
+		require.NoError(t, err)
+
+		t.Run("stacks the rows of matching sheets", func(t *testing.T) {
+			require.Len(t, frame.Fields, 3)
+			assert.Equal(t, 3, frame.Rows())
+			assert.Equal(t, "Item", frame.Fields[0].Name)
+			assert.Equal(t, "Amount", frame.Fields[1].Name)
+		})
+
+		t.Run("adds a column with the sheet of each row", func(t *testing.T) {
+			field := frame.Fields[2]
+			assert.Equal(t, unionSheetColumnName, field.Name)
+			assert.Equal(t, []string{"2026-01", "2026-01", "2026-02"}, []string{field.At(0).(string), field.At(1).(string), field.At(2).(string)})
+			assert.Equal(t, []string{"2026-01", "2026-02"}, meta["sheets"])
+		})
+
+		t.Run("reports skipped sheets as notices", func(t *testing.T) {
+			require.Len(t, frame.Meta.Notices, 2)
+			assert.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+			assert.Contains(t, frame.Meta.Notices[0].Text, `"2026-03"`)
+			assert.Equal(t, data.NoticeSeverityInfo, frame.Meta.Notices[1].Severity)
+			assert.Contains(t, frame.Meta.Notices[1].Text, `"2026-04"`)
+		})
+	})
+
+	t.Run("cell warnings refer to the cells of the source sheets", func(t *testing.T) {
+		gsd := &GoogleSheets{}
+		qm := &models.QueryModel{Spreadsheet: "someId", UnionSheets: "2026", ColumnTypes: map[string]string{"Amount": "number"}}
+		february := newTestGrid([]string{"Item", "Amount"}, []string{"Rent", "n/a"})
+		february.StartRow, february.StartColumn = 4, 1
+		sheetData := &spreadsheetData{Grids: []*sheetGrid{
+			{Title: "2026-01", Data: newTestGrid([]string{"Item", "Amount"}, []string{"Rent", "n/a"})},
+			{Title: "2026-02", Data: february},
+		}}
+
+		meta := map[string]any{}
+		_, err := gsd.transformUnionToDataFrame(context.Background(), sheetData, time.UTC, meta, "ref1", qm)
+		require.NoError(t, err)
+		warnings := meta["warnings"].([]string)
+		require.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "Cell '2026-01'!B2 ")
+		assert.Contains(t, warnings[1], "Cell '2026-02'!C6 ")
+	})
+}
@@ -1,7 +1,11 @@
 package googlesheets
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"google.golang.org/api/sheets/v4"
 )
 
 func findTimeField(frame *data.Frame) int {
@@ -25,3 +29,65 @@ func getExcelColumnName(columnNumber int) string {
 
 	return columnName
 }
+
+// getExcelColumnNumber returns the one-based number of a column name, such as 28 for AB.
+func getExcelColumnNumber(columnName string) int {
+	columnNumber := 0
+	for _, r := range columnName {
+		columnNumber = columnNumber*26 + int(r-'A') + 1
+	}
+	return columnNumber
+}
+
+// quoteSheetTitle quotes a sheet title so that it can be used in an A1 range.
+func quoteSheetTitle(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+// unquoteSheetTitle returns the title of a sheet as written in an A1 range.
+func unquoteSheetTitle(title string) string {
+	if len(title) >= 2 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		return strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+	return title
+}
+
+// getA1Range returns the A1 notation of a grid range of the sheet with the given title.
+// Unbounded ends of the grid range are left open, such as in 'Sheet1'!A2:C, and a range without
+// any end is the whole sheet.
+func getA1Range(title string, gridRange *sheets.GridRange) string {
+	a1 := quoteSheetTitle(title)
+	if gridRange.EndRowIndex == 0 && gridRange.EndColumnIndex == 0 {
+		return a1
+	}
+
+	startRow, endRow := strconv.FormatInt(gridRange.StartRowIndex+1, 10), ""
+	if gridRange.EndRowIndex > 0 {
+		endRow = strconv.FormatInt(gridRange.EndRowIndex, 10)
+	}
+	// Whole rows
+	if gridRange.EndColumnIndex == 0 {
+		return a1 + "!" + startRow + ":" + endRow
+	}
+
+	startColumn := getExcelColumnName(int(gridRange.StartColumnIndex) + 1)
+	endColumn := getExcelColumnName(int(gridRange.EndColumnIndex))
+	return a1 + "!" + startColumn + startRow + ":" + endColumn + endRow
+}
+
+// getGridDataRange returns the A1 notation of the cells of grid data of the sheet with the given title.
+func getGridDataRange(title string, gridData *sheets.GridData) string {
+	columnCount := 0
+	for _, row := range gridData.RowData {
+		columnCount = max(columnCount, len(row.Values))
+	}
+	if len(gridData.RowData) == 0 || columnCount == 0 {
+		return quoteSheetTitle(title)
+	}
+	return getA1Range(title, &sheets.GridRange{
+		StartRowIndex:    gridData.StartRow,
+		EndRowIndex:      gridData.StartRow + int64(len(gridData.RowData)),
+		StartColumnIndex: gridData.StartColumn,
+		EndColumnIndex:   gridData.StartColumn + int64(columnCount),
+	})
+}
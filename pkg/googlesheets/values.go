@@ -0,0 +1,214 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// valuesSampleRowCount is the number of data rows whose formats are fetched to infer the column types in values mode.
+const valuesSampleRowCount = 10
+
+// a1CellsPattern matches the cells of an A1 range, such as A1:D20, or its rows, such as 1:20.
+var a1CellsPattern = regexp.MustCompile(`^(?:([A-Z]+)([0-9]*)(?::([A-Z]+)[0-9]*)?|([0-9]+)(?::[0-9]+)?)$`)
+
+// valueRangeBounds is the sheet, the first cell and the last column of a range returned by the values API.
+type valueRangeBounds struct {
+	// sheet is the sheet as written in the range, which may be quoted
+	sheet string
+	title string
+	// startColumn, startRow and endColumn are one-based. endColumn is zero for the ranges of whole rows.
+	startColumn int
+	startRow    int
+	endColumn   int
+}
+
+// parseValueRange parses a range returned by the values API, such as 'My sheet'!A1:D20 or 'My sheet'!1:20.
+func parseValueRange(a1 string) (*valueRangeBounds, error) {
+	i := strings.LastIndex(a1, "!")
+	if i < 0 {
+		return nil, fmt.Errorf("unexpected range %q", a1)
+	}
+	match := a1CellsPattern.FindStringSubmatch(a1[i+1:])
+	if match == nil {
+		return nil, fmt.Errorf("unexpected range %q", a1)
+	}
+
+	bounds := &valueRangeBounds{
+		sheet:       a1[:i],
+		title:       unquoteSheetTitle(a1[:i]),
+		startColumn: 1,
+		startRow:    1,
+	}
+	if match[4] != "" {
+		bounds.startRow, _ = strconv.Atoi(match[4])
+		return bounds, nil
+	}
+	bounds.startColumn = getExcelColumnNumber(match[1])
+	if match[2] != "" {
+		bounds.startRow, _ = strconv.Atoi(match[2])
+	}
+	bounds.endColumn = bounds.startColumn
+	if match[3] != "" {
+		bounds.endColumn = getExcelColumnNumber(match[3])
+	}
+	return bounds, nil
+}
+
+// sampleRange returns the range of the first rows of the range.
+func (b *valueRangeBounds) sampleRange(rowCount int) string {
+	if b.endColumn == 0 {
+		return fmt.Sprintf("%s!%d:%d", b.sheet, b.startRow, b.startRow+rowCount-1)
+	}
+	return fmt.Sprintf("%s!%s%d:%s%d", b.sheet, getExcelColumnName(b.startColumn), b.startRow,
+		getExcelColumnName(b.endColumn), b.startRow+rowCount-1)
+}
+
+// getValuesSheetData gets the unformatted values of the ranges of a spreadsheet, and the formats of their first
+// rows only. The values are returned as grid data, with the formats of the first rows applied to the other rows,
+// so that they are read like the grid data of the grid mode.
+func getValuesSheetData(ctx context.Context, client client, qm *models.QueryModel, ranges []string, allSheets bool) (*spreadsheetData, error) {
+	// The values API returns the ranges in the order of the query, which tells the named ranges apart
+	queried := ranges
+	// The values API needs ranges: these are the sheets the grid mode would return
+	if len(ranges) == 0 {
+		result, err := client.GetSpreadsheet(ctx, qm.Spreadsheet, nil, false)
+		if err != nil {
+			return nil, handleGoogleAPIError(ctx, err)
+		}
+		for _, sheet := range result.Sheets {
+			if sheet.Properties == nil {
+				continue
+			}
+			ranges = append(ranges, quoteSheetTitle(sheet.Properties.Title))
+			if !allSheets {
+				break
+			}
+		}
+	}
+
+	valuesResult, err := client.GetValues(ctx, qm.Spreadsheet, ranges)
+	if err != nil {
+		return nil, handleGoogleAPIError(ctx, err)
+	}
+
+	first, count := getHeaderRows(qm)
+	dataStart := first + count
+	if qm.NoHeader {
+		dataStart = first
+	}
+	bounds := make([]*valueRangeBounds, 0, len(valuesResult.ValueRanges))
+	sampleRanges := make([]string, 0, len(valuesResult.ValueRanges))
+	for _, valueRange := range valuesResult.ValueRanges {
+		b, err := parseValueRange(valueRange.Range)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, b)
+		sampleRanges = append(sampleRanges, b.sampleRange(dataStart+valuesSampleRowCount))
+	}
+
+	sample, err := client.GetSpreadsheet(ctx, qm.Spreadsheet, sampleRanges, true)
+	if err != nil {
+		return nil, handleGoogleAPIError(ctx, err)
+	}
+	sampleGrids := map[string]*sheets.GridData{}
+	for _, sheet := range sample.Sheets {
+		if sheet.Properties == nil {
+			continue
+		}
+		for _, gridData := range sheet.Data {
+			sampleGrids[getSampleGridKey(sheet.Properties.Title, gridData.StartRow, gridData.StartColumn)] = gridData
+		}
+	}
+
+	namedRanges := getQueriedNamedRanges(sample, queried)
+	sheetData := &spreadsheetData{}
+	if sample.Properties != nil {
+		sheetData.TimeZone = sample.Properties.TimeZone
+	}
+	for i, valueRange := range valuesResult.ValueRanges {
+		b := bounds[i]
+		startRow, startColumn := int64(b.startRow-1), int64(b.startColumn-1)
+		gridData := newValuesGridData(valueRange.Values, sampleGrids[getSampleGridKey(b.title, startRow, startColumn)], dataStart)
+		gridData.StartRow, gridData.StartColumn = startRow, startColumn
+		grid := &sheetGrid{Title: b.title, Data: gridData}
+		if i < len(queried) {
+			if namedRange := findQueriedNamedRange(namedRanges, queried[i]); namedRange != nil {
+				grid.NamedRange = namedRange.Name
+				grid.ResolvedRange = getA1Range(b.title, namedRange.Range)
+			}
+		}
+		sheetData.Grids = append(sheetData.Grids, grid)
+	}
+	return sheetData, nil
+}
+
+func getSampleGridKey(title string, startRow, startColumn int64) string {
+	return fmt.Sprintf("%s!%d:%d", title, startRow, startColumn)
+}
+
+// newValuesGridData returns grid data for the values of a range. The first rows are those of the sample grid
+// data, and the number cells of the other rows get the format of the first formatted data cell of their column.
+func newValuesGridData(values [][]any, sample *sheets.GridData, dataStart int) *sheets.GridData {
+	formats := map[int]*sheets.CellData{}
+	sampleRows := []*sheets.RowData{}
+	if sample != nil {
+		sampleRows = sample.RowData
+		for _, row := range sampleRows[min(dataStart, len(sampleRows)):] {
+			for j, cell := range row.Values {
+				if _, ok := formats[j]; !ok && cell != nil && cell.FormattedValue != "" &&
+					(cell.EffectiveFormat != nil || cell.UserEnteredFormat != nil) {
+					formats[j] = cell
+				}
+			}
+		}
+	}
+
+	gridData := &sheets.GridData{RowData: make([]*sheets.RowData, 0, len(values))}
+	for i, row := range values {
+		if i < len(sampleRows) {
+			gridData.RowData = append(gridData.RowData, sampleRows[i])
+			continue
+		}
+		rowData := &sheets.RowData{Values: make([]*sheets.CellData, 0, len(row))}
+		for j, value := range row {
+			rowData.Values = append(rowData.Values, newValueCellData(value, formats[j]))
+		}
+		gridData.RowData = append(gridData.RowData, rowData)
+	}
+	return gridData
+}
+
+// newValueCellData returns the cell data of an unformatted value, with the format of the given cell for numbers.
+func newValueCellData(value any, format *sheets.CellData) *sheets.CellData {
+	cell := &sheets.CellData{}
+	switch v := value.(type) {
+	case nil:
+	case float64:
+		cell.FormattedValue = strconv.FormatFloat(v, 'f', -1, 64)
+		cell.EffectiveValue = &sheets.ExtendedValue{NumberValue: &v}
+		// Times are serial numbers, which the format tells apart from numbers
+		if format != nil {
+			cell.EffectiveFormat = format.EffectiveFormat
+			cell.UserEnteredFormat = format.UserEnteredFormat
+		}
+	case bool:
+		cell.FormattedValue = strings.ToUpper(strconv.FormatBool(v))
+		cell.EffectiveValue = &sheets.ExtendedValue{BoolValue: &v}
+	case string:
+		if v != "" {
+			cell.FormattedValue = v
+			cell.EffectiveValue = &sheets.ExtendedValue{StringValue: &v}
+		}
+	default:
+		cell.FormattedValue = fmt.Sprint(v)
+	}
+	return cell
+}
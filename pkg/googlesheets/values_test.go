@@ -0,0 +1,142 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestParseValueRange(t *testing.T) {
+	bounds, err := parseValueRange("'Bob''s data'!B2:AB20")
+	require.NoError(t, err)
+	assert.Equal(t, &valueRangeBounds{sheet: "'Bob''s data'", title: "Bob's data", startColumn: 2, startRow: 2, endColumn: 28}, bounds)
+	assert.Equal(t, "'Bob''s data'!B2:AB6", bounds.sampleRange(5))
+
+	bounds, err = parseValueRange("Sheet1!C3")
+	require.NoError(t, err)
+	assert.Equal(t, "Sheet1!C3:C4", bounds.sampleRange(2))
+
+	bounds, err = parseValueRange("Sheet1!2:5")
+	require.NoError(t, err)
+	assert.Equal(t, &valueRangeBounds{sheet: "Sheet1", title: "Sheet1", startColumn: 1, startRow: 2}, bounds)
+	assert.Equal(t, "Sheet1!2:4", bounds.sampleRange(3))
+
+	_, err = parseValueRange("Sheet1")
+	assert.Error(t, err)
+}
+
+func TestValuesFetchModeNamedRange(t *testing.T) {
+	client := &fakeClient{}
+	gsd := &GoogleSheets{
+		Cache: cache.New(300*time.Second, 50*time.Second),
+	}
+	qm := &models.QueryModel{Spreadsheet: "someId", Range: "KPI_Table", FetchMode: models.FetchModeValues}
+
+	client.On("GetValues", mock.Anything, "someId", []string{"KPI_Table"}).Return(&sheets.BatchGetValuesResponse{
+		ValueRanges: []*sheets.ValueRange{{Range: "Sheet1!B2:C3", Values: [][]any{{"Region", "Amount"}, {"North", 10.0}}}},
+	}, nil).Once()
+	client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Sheet1!B2:C12"}, true).Return(&sheets.Spreadsheet{
+		NamedRanges: []*sheets.NamedRange{{Name: "KPI_Table", Range: &sheets.GridRange{StartRowIndex: 1, EndRowIndex: 10, StartColumnIndex: 1, EndColumnIndex: 3}}},
+		Sheets: []*sheets.Sheet{{Properties: &sheets.SheetProperties{Title: "Sheet1"}, Data: []*sheets.GridData{
+			{StartRow: 1, StartColumn: 1, RowData: newTestGrid([]string{"Region", "Amount"}).RowData},
+		}}},
+	}, nil).Once()
+
+	sheetData, _, err := gsd.getSheetData(context.Background(), client, qm)
+	require.NoError(t, err)
+	require.Len(t, sheetData.Grids, 1)
+	assert.Equal(t, "KPI_Table", sheetData.Grids[0].NamedRange)
+	assert.Equal(t, "'Sheet1'!B2:C10", sheetData.Grids[0].ResolvedRange)
+	client.AssertExpectations(t)
+}
+
+func TestValuesFetchMode(t *testing.T) {
+	client := &fakeClient{}
+	gsd := &GoogleSheets{
+		Cache: cache.New(300*time.Second, 50*time.Second),
+	}
+	qm := &models.QueryModel{Spreadsheet: "someId", Range: "Sheet1!A1:C", FetchMode: models.FetchModeValues, CacheDurationSeconds: 60}
+
+	values := [][]any{{"Date", "Amount", "Region"}}
+	for i := range 20 {
+		values = append(values, []any{46082.0 + float64(i), float64(i * 10), "North"})
+	}
+	values[20] = []any{"", "", "South"}
+	client.On("GetValues", mock.Anything, "someId", []string{"Sheet1!A1:C"}).Return(&sheets.BatchGetValuesResponse{
+		ValueRanges: []*sheets.ValueRange{{Range: "Sheet1!A1:C21", Values: values}},
+	}, nil).Once()
+
+	dateFormat := &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "DATE", Pattern: "yyyy-mm-dd"}}
+	euroFormat := &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "NUMBER", Pattern: "#,##0.00 €"}}
+	sample := newTestGrid([]string{"Date", "Amount", "Region"})
+	for i := range 10 {
+		serial, amount, region := 46082.0+float64(i), float64(i*10), "North"
+		sample.RowData = append(sample.RowData, &sheets.RowData{Values: []*sheets.CellData{
+			{FormattedValue: "2026-03-01", EffectiveValue: &sheets.ExtendedValue{NumberValue: &serial}, EffectiveFormat: dateFormat, UserEnteredFormat: dateFormat},
+			{FormattedValue: "0.00 €", EffectiveValue: &sheets.ExtendedValue{NumberValue: &amount}, EffectiveFormat: euroFormat, UserEnteredFormat: euroFormat},
+			{FormattedValue: region, EffectiveValue: &sheets.ExtendedValue{StringValue: &region}},
+		}})
+	}
+	client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Sheet1!A1:C11"}, true).Return(&sheets.Spreadsheet{
+		Properties: &sheets.SpreadsheetProperties{TimeZone: "Europe/Paris"},
+		Sheets:     []*sheets.Sheet{{Properties: &sheets.SheetProperties{Title: "Sheet1"}, Data: []*sheets.GridData{sample}}},
+	}, nil).Once()
+
+	sheetData, meta, err := gsd.getSheetData(context.Background(), client, qm)
+	require.NoError(t, err)
+	assert.Equal(t, false, meta["hit"])
+	assert.Equal(t, "Europe/Paris", sheetData.TimeZone)
+	require.Len(t, sheetData.Grids, 1)
+	assert.Len(t, sheetData.Grids[0].Data.RowData, 21)
+
+	frame, err := gsd.transformSheetToDataFrame(context.Background(), sheetData.Grids[0].Data, time.UTC, map[string]any{}, "A", qm)
+	require.NoError(t, err)
+	require.Equal(t, 20, frame.Rows())
+
+	// The types and units inferred from the sample apply to the other rows
+	assert.Equal(t, time.Date(2026, time.March, 19, 0, 0, 0, 0, time.UTC), *frame.Fields[0].At(18).(*time.Time))
+	assert.Nil(t, frame.Fields[0].At(19))
+	assert.Equal(t, 180.0, *frame.Fields[1].At(18).(*float64))
+	assert.Equal(t, "currencyEUR", frame.Fields[1].Config.Unit)
+	assert.Equal(t, "South", *frame.Fields[2].At(19).(*string))
+
+	t.Run("fetch mode is part of the cache key", func(t *testing.T) {
+		_, meta, err := gsd.getSheetData(context.Background(), client, qm)
+		require.NoError(t, err)
+		assert.Equal(t, true, meta["hit"])
+
+		gridQuery := *qm
+		gridQuery.FetchMode = ""
+		client.On("GetSpreadsheet", mock.Anything, "someId", []string{"Sheet1!A1:C"}, true).Return(newTestSpreadsheet("Sheet1"), nil).Once()
+		_, meta, err = gsd.getSheetData(context.Background(), client, &gridQuery)
+		require.NoError(t, err)
+		assert.Equal(t, false, meta["hit"])
+		client.AssertExpectations(t)
+	})
+
+	t.Run("header rows are part of the cache key", func(t *testing.T) {
+		for _, headerQuery := range []models.QueryModel{
+			{HeaderRowIndex: 1},
+			{HeaderRowCount: 2},
+			{NoHeader: true},
+		} {
+			headerQuery.Spreadsheet, headerQuery.Range, headerQuery.FetchMode = qm.Spreadsheet, qm.Range, qm.FetchMode
+			assert.NotEqual(t, getSheetDataCacheKey(qm, qm.GetRanges()), getSheetDataCacheKey(&headerQuery, headerQuery.GetRanges()))
+		}
+	})
+
+	t.Run("unknown fetch mode is a downstream error", func(t *testing.T) {
+		_, _, err := gsd.getSheetData(context.Background(), client, &models.QueryModel{Spreadsheet: "someId", FetchMode: "cells"})
+		require.Error(t, err)
+		assert.True(t, backend.IsDownstreamError(err))
+	})
+}
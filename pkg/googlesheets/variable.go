@@ -0,0 +1,137 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	variableSortAsc  = "asc"
+	variableSortDesc = "desc"
+)
+
+// VariableQuery queries a spreadsheet and returns a frame with the distinct values of the value field
+// of the query, along with their texts, as expected by template variables.
+func (gs *GoogleSheets) VariableQuery(ctx context.Context, refID string, qm *models.QueryModel, config models.DatasourceSettings, timeRange backend.TimeRange) backend.DataResponse {
+	dr := gs.Query(ctx, refID, qm, config, timeRange)
+	if dr.Error != nil {
+		return dr
+	}
+	if len(dr.Frames) == 0 {
+		return backend.DataResponse{Frames: data.Frames{newVariableFrame(refID, nil, nil)}}
+	}
+
+	frame, err := transformVariableFrame(dr.Frames[0], qm)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// transformVariableFrame returns a frame with value and text fields, holding the distinct values of the
+// value field of the query. Rows without value or text, or not matching the filter, are skipped. As when the
+// variables were evaluated in the frontend, a missing value field returns no values, a missing label field
+// uses the values as texts, and a missing filter field doesn't filter the rows.
+func transformVariableFrame(frame *data.Frame, qm *models.QueryModel) (*data.Frame, error) {
+	valueField := getVariableField(frame, qm.ValueField)
+	if valueField == nil {
+		return newVariableFrame(frame.RefID, nil, nil), nil
+	}
+	labelField := getVariableField(frame, qm.LabelField)
+	if labelField == nil {
+		labelField = valueField
+	}
+	var filterField *data.Field
+	if qm.FilterValue != "" {
+		filterField = getVariableField(frame, qm.FilterField)
+	}
+
+	values, texts := []string{}, []string{}
+	seen := map[string]bool{}
+	for row := 0; row < frame.Rows(); row++ {
+		value, ok := getVariableCell(valueField, row)
+		if !ok || seen[value] {
+			continue
+		}
+		text, ok := getVariableCell(labelField, row)
+		if !ok {
+			continue
+		}
+		if filterField != nil {
+			if filterValue, _ := getVariableCell(filterField, row); filterValue != qm.FilterValue {
+				continue
+			}
+		}
+		seen[value] = true
+		values = append(values, value)
+		texts = append(texts, text)
+	}
+
+	switch strings.ToLower(qm.VariableSort) {
+	case "":
+	case variableSortAsc, variableSortDesc:
+		sortVariableValues(values, texts, strings.ToLower(qm.VariableSort) == variableSortDesc)
+	default:
+		return nil, backend.DownstreamError(fmt.Errorf("unknown variable sort %q", qm.VariableSort))
+	}
+
+	return newVariableFrame(frame.RefID, values, texts), nil
+}
+
+// getVariableField returns the field with the given name, or nil if there is none.
+func getVariableField(frame *data.Frame, name string) *data.Field {
+	if name == "" {
+		return nil
+	}
+	field, _ := frame.FieldByName(name)
+	return field
+}
+
+// getVariableCell returns the text of a cell. It returns false for null and empty cells.
+func getVariableCell(field *data.Field, row int) (string, bool) {
+	value, ok := cellValue(field.At(row))
+	if !ok {
+		return "", false
+	}
+	text := formatCellValue(value)
+	return text, text != ""
+}
+
+// sortVariableValues sorts the values by their texts.
+func sortVariableValues(values, texts []string, descending bool) {
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		if descending {
+			return strings.Compare(texts[b], texts[a])
+		}
+		return strings.Compare(texts[a], texts[b])
+	})
+
+	sortedValues, sortedTexts := slices.Clone(values), slices.Clone(texts)
+	for i, index := range indices {
+		values[i], texts[i] = sortedValues[index], sortedTexts[index]
+	}
+}
+
+// newVariableFrame returns a frame with value and text fields.
+func newVariableFrame(refID string, values, texts []string) *data.Frame {
+	if values == nil {
+		values, texts = []string{}, []string{}
+	}
+	frame := data.NewFrame(refID,
+		data.NewField("value", nil, values),
+		data.NewField("text", nil, texts),
+	)
+	frame.RefID = refID
+	return frame
+}
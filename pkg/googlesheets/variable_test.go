@@ -0,0 +1,85 @@
+package googlesheets
+
+import (
+	"testing"
+
+	"github.com/grafana/google-sheets-datasource/pkg/models"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformVariableFrame(t *testing.T) {
+	str := func(s string) *string { return &s }
+	newFrame := func() *data.Frame {
+		frame := data.NewFrame("plugins",
+			data.NewField("id", nil, []*string{str("3"), str("1"), str("2"), str("1"), nil, str("4")}),
+			data.NewField("name", nil, []*string{str("Plugin C"), str("Plugin A"), str("Plugin B"), str("Plugin A"), str("Plugin E"), str("")}),
+			data.NewField("squad", nil, []*string{str("frontend"), str("frontend"), str("backend"), str("frontend"), str("frontend"), str("frontend")}),
+		)
+		frame.RefID = "A"
+		return frame
+	}
+	fieldValues := func(field *data.Field) []string {
+		values := make([]string, field.Len())
+		for i := range values {
+			values[i] = field.At(i).(string)
+		}
+		return values
+	}
+
+	t.Run("distinct values with their texts", func(t *testing.T) {
+		frame, err := transformVariableFrame(newFrame(), &models.QueryModel{ValueField: "id", LabelField: "name"})
+		require.NoError(t, err)
+		assert.Equal(t, "A", frame.RefID)
+		assert.Equal(t, []string{"3", "1", "2"}, fieldValues(frame.Fields[0]))
+		assert.Equal(t, []string{"Plugin C", "Plugin A", "Plugin B"}, fieldValues(frame.Fields[1]))
+	})
+
+	t.Run("texts default to values", func(t *testing.T) {
+		frame, err := transformVariableFrame(newFrame(), &models.QueryModel{ValueField: "squad"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend", "backend"}, fieldValues(frame.Fields[0]))
+		assert.Equal(t, fieldValues(frame.Fields[0]), fieldValues(frame.Fields[1]))
+	})
+
+	t.Run("filtered and sorted", func(t *testing.T) {
+		frame, err := transformVariableFrame(newFrame(), &models.QueryModel{
+			ValueField:   "id",
+			LabelField:   "name",
+			FilterField:  "squad",
+			FilterValue:  "frontend",
+			VariableSort: "DESC",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3", "1"}, fieldValues(frame.Fields[0]))
+		assert.Equal(t, []string{"Plugin C", "Plugin A"}, fieldValues(frame.Fields[1]))
+	})
+
+	t.Run("missing fields are ignored", func(t *testing.T) {
+		for _, qm := range []*models.QueryModel{{}, {ValueField: "unknown"}} {
+			frame, err := transformVariableFrame(newFrame(), qm)
+			require.NoError(t, err)
+			assert.Equal(t, 0, frame.Rows())
+			assert.Len(t, frame.Fields, 2)
+		}
+
+		frame, err := transformVariableFrame(newFrame(), &models.QueryModel{
+			ValueField:  "squad",
+			LabelField:  "unknown",
+			FilterField: "unknown",
+			FilterValue: "frontend",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend", "backend"}, fieldValues(frame.Fields[0]))
+		assert.Equal(t, fieldValues(frame.Fields[0]), fieldValues(frame.Fields[1]))
+	})
+
+	t.Run("unknown sort is a downstream error", func(t *testing.T) {
+		_, err := transformVariableFrame(newFrame(), &models.QueryModel{ValueField: "id", VariableSort: "random"})
+		require.Error(t, err)
+		assert.True(t, backend.IsDownstreamError(err))
+	})
+}
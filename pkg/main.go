@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+	// Spreadsheet timezones are loaded by name, whether or not the host has a timezone database
+	_ "time/tzdata"
 
 	"github.com/grafana/google-sheets-datasource/pkg/googlesheets"
 
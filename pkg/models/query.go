@@ -3,20 +3,145 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 )
 
+// AllSheetsRange is the wildcard range that selects every sheet of a spreadsheet.
+const AllSheetsRange = "*"
+
+// Query types. Queries without query type return the grid data of the spreadsheet.
+const (
+	// QueryTypeVariable is the query type of template variable queries, which return value and text fields.
+	QueryTypeVariable = "variable"
+	// QueryTypeSpreadsheets returns the spreadsheets the datasource has access to.
+	QueryTypeSpreadsheets = "spreadsheets"
+	// QueryTypeSheets returns the sheets of the spreadsheet.
+	QueryTypeSheets = "sheets"
+	// QueryTypeFileInfo returns the Drive file metadata of the spreadsheet.
+	QueryTypeFileInfo = "fileInfo"
+)
+
+// Fetch modes of the spreadsheet data.
+const (
+	// FetchModeGrid fetches the cells with their formats. This is the default.
+	FetchModeGrid = "grid"
+	// FetchModeValues fetches the unformatted values only, and the formats of a few rows to infer the column types.
+	FetchModeValues = "values"
+)
+
+// Cache modes of the spreadsheet data.
+const (
+	// CacheModeTTL serves the cached data until it expires. This is the default.
+	CacheModeTTL = "ttl"
+	// CacheModeModifiedTime serves the cached data only while the Drive modified time of the spreadsheet is unchanged.
+	CacheModeModifiedTime = "modifiedTime"
+	// CacheModeStaleWhileRevalidate serves the expired cached data for a while, and refreshes it in the background.
+	CacheModeStaleWhileRevalidate = "staleWhileRevalidate"
+)
+
 // QueryModel represents a spreadsheet query.
 type QueryModel struct {
-	Spreadsheet          string `json:"spreadsheet"`
-	Range                string `json:"range"`
-	CacheDurationSeconds int    `json:"cacheDurationSeconds"`
-	UseTimeFilter        bool   `json:"useTimeFilter"`
+	Spreadsheet          string   `json:"spreadsheet"`
+	Range                string   `json:"range"`
+	Ranges               []string `json:"ranges,omitempty"`
+	CacheDurationSeconds int      `json:"cacheDurationSeconds"`
+	UseTimeFilter        bool     `json:"useTimeFilter"`
+	// CacheMode is ttl, modifiedTime or staleWhileRevalidate. The modifiedTime mode allows long cache durations
+	// that still see edits, and the staleWhileRevalidate mode spares the API latency to the first query after expiry.
+	CacheMode string `json:"cacheMode,omitempty"`
+	// MaxStaleSeconds is how long after expiry the cached data may be served in the staleWhileRevalidate mode.
+	MaxStaleSeconds int `json:"maxStaleSeconds,omitempty"`
+	// FetchMode is grid or values. The values mode downloads much less data for large sheets.
+	FetchMode string `json:"fetchMode,omitempty"`
+
+	// UnionSheets is a regular expression on sheet titles. The matching sheets are
+	// stacked into a single frame, with Range applied to each of them.
+	UnionSheets string `json:"unionSheets,omitempty"`
+
+	// HeaderRowIndex is the zero-based index of the first header row within the range.
+	// Rows above it, such as title banners, are skipped.
+	HeaderRowIndex int `json:"headerRowIndex,omitempty"`
+	// HeaderRowCount is the number of header rows, flattened into a single field name. Defaults to 1.
+	HeaderRowCount int `json:"headerRowCount,omitempty"`
+	// NoHeader treats every row as data and generates the field names.
+	NoHeader bool `json:"noHeader,omitempty"`
+
+	// ColumnTypes forces the type of columns by header: time, number, string or boolean.
+	// Cells that can't be converted are returned as nulls.
+	ColumnTypes map[string]string `json:"columnTypes,omitempty"`
+	// TimeFormat is the Go layout used to parse the formatted value of time cells.
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// Timezone overrides the timezone of the spreadsheet, in which times without timezone are read.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Filters are applied to the rows of the frames before they are returned.
+	Filters []FilterModel `json:"filters,omitempty"`
+
+	// GroupBy lists the columns whose values group the rows for the aggregations.
+	GroupBy []string `json:"groupBy,omitempty"`
+	// GroupByInterval truncates the time columns of GroupBy to buckets of this duration, such as "1h" or "1d".
+	GroupByInterval string `json:"groupByInterval,omitempty"`
+	// Aggregations are computed for each group, or over all the rows without GroupBy.
+	Aggregations []AggregationModel `json:"aggregations,omitempty"`
+
+	// Downsample aggregates the rows into buckets of the first time field, sized
+	// from MaxDataPoints and Interval, so that large sheets return fewer points.
+	Downsample bool `json:"downsample,omitempty"`
+	// DownsampleFunctions sets the aggregation function of number columns when downsampling. Defaults to avg.
+	DownsampleFunctions map[string]string `json:"downsampleFunctions,omitempty"`
+
+	// Freshness fetches the Drive modified time, last modifying user and version of the spreadsheet into the frame meta.
+	Freshness bool `json:"freshness,omitempty"`
+	// FreshnessFrame also returns them as a single row frame, along with the age of the last change.
+	FreshnessFrame bool `json:"freshnessFrame,omitempty"`
+
+	// StreamIntervalSeconds is how often a streaming query polls the spreadsheet. Defaults to 30 seconds, at least 5.
+	StreamIntervalSeconds int `json:"streamIntervalSeconds,omitempty"`
+
+	// ValueField is the column holding the values of a variable query.
+	ValueField string `json:"valueField,omitempty"`
+	// LabelField is the column holding the texts of a variable query. Defaults to ValueField.
+	LabelField string `json:"labelField,omitempty"`
+	// FilterField and FilterValue keep the rows of a variable query whose FilterField is FilterValue.
+	FilterField string `json:"filterField,omitempty"`
+	FilterValue string `json:"filterValue,omitempty"`
+	// VariableSort sorts the values of a variable query by text: asc or desc. They keep the sheet order by default.
+	VariableSort string `json:"variableSort,omitempty"`
 
 	// Not from JSON
+	QueryType     string            `json:"-"`
 	TimeRange     backend.TimeRange `json:"-"`
 	MaxDataPoints int64             `json:"-"`
+	Interval      time.Duration     `json:"-"`
+}
+
+// FilterModel represents a filter on the values of a column.
+type FilterModel struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"` // = | != | < | <= | > | >= | contains | regex | in
+	// Value is compared to the cells of the column. The in operator takes comma separated values.
+	Value string `json:"value"`
+}
+
+// AggregationModel represents an aggregation of the values of a column.
+type AggregationModel struct {
+	Column   string `json:"column"`
+	Function string `json:"function"` // sum | avg | min | max | count | first | last
+	// Alias is the name of the aggregated field. Defaults to the column followed by the function.
+	Alias string `json:"alias,omitempty"`
+}
+
+// GetRanges returns the ranges to query. Ranges takes precedence over the single Range.
+func (qm *QueryModel) GetRanges() []string {
+	if len(qm.Ranges) > 0 {
+		return qm.Ranges
+	}
+	if qm.Range != "" {
+		return []string{qm.Range}
+	}
+	return nil
 }
 
 // GetQueryModel returns the well typed query model
@@ -29,7 +154,9 @@ func GetQueryModel(query backend.DataQuery) (*QueryModel, error) {
 	}
 
 	// Copy directly from the well typed query
+	model.QueryType = query.QueryType
 	model.TimeRange = query.TimeRange
 	model.MaxDataPoints = query.MaxDataPoints
+	model.Interval = query.Interval
 	return model, nil
 }
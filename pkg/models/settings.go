@@ -20,6 +20,16 @@ type DatasourceSettings struct {
 	AuthenticationType string `json:"authenticationType"`
 	PrivateKeyPath     string `json:"privateKeyPath"`
 	DefaultSheetID     string `json:"defaultSheetID"`
+	// RootFolderID restricts the listed spreadsheets to those of a Drive folder
+	RootFolderID string `json:"rootFolderId"`
+	// IncludeSharedDrives lists the spreadsheets of shared drives along with those of My Drive
+	IncludeSharedDrives bool `json:"includeSharedDrives"`
+	// CacheSizeLimitMB bounds the memory of the cached spreadsheet data, which is unbounded when 0
+	CacheSizeLimitMB int `json:"cacheSizeLimitMB"`
+	// DiskCache persists the cached spreadsheet data on disk, so that it survives plugin restarts
+	DiskCache bool `json:"diskCache"`
+	// DiskCacheEncrypt encrypts the data persisted on disk with the diskCacheKey secure setting, and keeps it off disk without a key
+	DiskCacheEncrypt bool `json:"diskCacheEncrypt"`
 
 	// Saved in secure JSON
 	PrivateKey string `json:"-"`
@@ -5,10 +5,19 @@ import {
   ScopedVars,
   SelectableValue,
   CoreApp,
+  LiveChannelScope,
 } from '@grafana/data';
-import { DataSourceWithBackend, getTemplateSrv, TemplateSrv } from '@grafana/runtime';
-import { GoogleSheetsDataSourceOptions, SheetsQuery, SheetsVariableQuery } from './types';
-import { Observable } from 'rxjs';
+import { DataSourceWithBackend, getGrafanaLiveSrv, getTemplateSrv, TemplateSrv } from '@grafana/runtime';
+import {
+  ColumnSchema,
+  GoogleSheetsDataSourceOptions,
+  RangesInfo,
+  SheetInfo,
+  SheetsQuery,
+  SheetsVariableQuery,
+  SpreadsheetInfo,
+} from './types';
+import { from, map, merge, mergeMap, Observable } from 'rxjs';
 import { trackRequest } from 'tracking';
 import { SheetsVariableSupport } from 'variables';
 
@@ -25,18 +34,55 @@ export class DataSource extends DataSourceWithBackend<SheetsQuery, GoogleSheetsD
 
   query(request: DataQueryRequest<SheetsQuery>): Observable<DataQueryResponse> {
     trackRequest(request);
-    return super.query(request);
+    const streaming = request.targets.filter((target) => target.stream && !target.hide);
+    if (!streaming.length) {
+      return super.query(request);
+    }
+
+    // Streaming queries are polled by the backend, which pushes their frames when the spreadsheet changes.
+    // The backend keys the channel of each query, and only accepts subscriptions to that channel.
+    const streams = streaming.map((target) => {
+      const query = this.applyTemplateVariables(target, request.scopedVars);
+      return from(this.postResource<{ path: string }>('stream-path', query)).pipe(
+        mergeMap(({ path }) =>
+          getGrafanaLiveSrv().getDataStream({
+            key: `${request.requestId}-${target.refId}`,
+            addr: {
+              scope: LiveChannelScope.DataSource,
+              namespace: this.uid,
+              path,
+              data: query,
+            },
+          })
+        ),
+        // The channels are shared by the panels running the same query, so the frames are sent without refId
+        map((response) => {
+          response.data.forEach((frame) => {
+            frame.refId = target.refId;
+          });
+          return response;
+        })
+      );
+    });
+    const targets = request.targets.filter((target) => !target.stream);
+    return merge(...streams, ...(targets.length ? [super.query({ ...request, targets })] : []));
   }
 
   // Enables default annotation support for 7.2+
   annotations = {};
 
-  // Support template variables for spreadsheet and range
+  // Support template variables for spreadsheet, range and filter values
   applyTemplateVariables(query: SheetsQuery, scopedVars: ScopedVars) {
     return {
       ...query,
       spreadsheet: this.interpolateVariable(query.spreadsheet, scopedVars) ?? '',
       range: this.interpolateVariable(query.range, scopedVars),
+      ranges: query.ranges?.map((range) => this.templateSrv.replace(range, scopedVars)),
+      filters: query.filters?.map((filter) => ({
+        ...filter,
+        // Multi-value variables are joined with commas, as expected by the in operator
+        value: filter.value ? this.templateSrv.replace(filter.value, scopedVars, 'csv') : filter.value,
+      })),
     };
   }
 
@@ -60,21 +106,44 @@ export class DataSource extends DataSourceWithBackend<SheetsQuery, GoogleSheetsD
     return this.templateSrv.replace(value, item);
   }
 
-  async getSpreadSheets(): Promise<Array<SelectableValue<string>>> {
-    return this.getResource('spreadsheets').then(({ spreadsheets }) =>
-      spreadsheets
-        ? Object.entries(spreadsheets).map(
-            ([value, label]) =>
-              ({
-                label,
-                value,
-                description: value,
-              }) as SelectableValue<string>
-          )
-        : []
+  async getSpreadSheets(search?: string, pageToken?: string): Promise<Array<SelectableValue<string>>> {
+    const params = { search: search ?? '', pageToken: pageToken ?? '' };
+    return this.getResource('spreadsheets', params).then(({ spreadsheets }) =>
+      ((spreadsheets ?? []) as SpreadsheetInfo[]).map(
+        (spreadsheet) =>
+          ({
+            label: spreadsheet.name,
+            value: spreadsheet.id,
+            // Tells apart spreadsheets with the same name
+            description: [spreadsheet.parentPath, spreadsheet.owner].filter(Boolean).join(' · ') || spreadsheet.id,
+          }) as SelectableValue<string>
+      )
+    );
+  }
+
+  async getSheets(spreadsheet: string): Promise<SheetInfo[]> {
+    return this.getResource('sheets', { spreadsheet }).then(({ sheets }) => sheets ?? []);
+  }
+
+  async getRanges(spreadsheet: string): Promise<RangesInfo> {
+    return this.getResource('ranges', { spreadsheet }).then(
+      ({ ranges }) => ranges ?? { namedRanges: [], protectedRanges: [] }
     );
   }
 
+  async getSchema(
+    query: Pick<SheetsQuery, 'spreadsheet' | 'range' | 'headerRowIndex' | 'headerRowCount' | 'noHeader'>
+  ): Promise<ColumnSchema[]> {
+    const { spreadsheet, range, headerRowIndex, headerRowCount, noHeader } = query;
+    return this.getResource('schema', {
+      spreadsheet,
+      range: range ?? '',
+      headerRowIndex: headerRowIndex ?? 0,
+      headerRowCount: headerRowCount ?? 1,
+      noHeader: noHeader ?? false,
+    }).then(({ schema }) => schema ?? []);
+  }
+
   getDefaultQuery(app: CoreApp): Partial<SheetsQuery> {
     return { spreadsheet: this.instanceSettings.jsonData.defaultSheetID || '' };
   }
@@ -1,19 +1,21 @@
 import {
   DataSourcePluginOptionsEditorProps,
+  onUpdateDatasourceJsonDataOption,
+  onUpdateDatasourceJsonDataOptionChecked,
   onUpdateDatasourceSecureJsonDataOption,
   SelectableValue,
 } from '@grafana/data';
 import { AuthConfig } from '@grafana/google-sdk';
 import { DataSourceDescription } from '@grafana/plugin-ui';
-import { Field, SecretInput, SegmentAsync, Divider } from '@grafana/ui';
-import React, { useState, useEffect, useRef } from 'react';
+import { Field, Input, InlineSwitch, SecretInput, SegmentAsync, Divider } from '@grafana/ui';
+import React, { useState, useEffect, useMemo, useRef } from 'react';
 import {
   GoogleSheetsSecureJSONData,
   googleSheetsAuthTypes,
   GoogleSheetsAuth,
   GoogleSheetsDataSourceOptions,
 } from '../types';
-import { getBackwardCompatibleOptions } from '../utils';
+import { debounceAsync, getBackwardCompatibleOptions } from '../utils';
 import { ConfigurationHelp } from './ConfigurationHelp';
 import { getDataSourceSrv } from '@grafana/runtime';
 import { DataSource } from '../DataSource';
@@ -36,19 +38,46 @@ export function ConfigEditor(props: Props) {
       props.onOptionsChange({
         ...options,
         secureJsonFields: { ...options.secureJsonFields, apiKey: false },
-        secureJsonData: { apiKey: '' },
+        secureJsonData: { ...options.secureJsonData, apiKey: '' },
         jsonData: options.jsonData,
       }),
     onChange: onUpdateDatasourceSecureJsonDataOption(props, 'apiKey'),
   };
 
-  const loadSheetIDs = async () => {
+  const diskCacheKeyProps = {
+    isConfigured: Boolean(options.secureJsonFields.diskCacheKey),
+    value: options.secureJsonData?.diskCacheKey || '',
+    placeholder: 'Enter encryption key',
+    id: 'diskCacheKey',
+    onReset: () =>
+      props.onOptionsChange({
+        ...options,
+        secureJsonFields: { ...options.secureJsonFields, diskCacheKey: false },
+        secureJsonData: { ...options.secureJsonData, diskCacheKey: '' },
+      }),
+    onChange: onUpdateDatasourceSecureJsonDataOption(props, 'diskCacheKey'),
+  };
+
+  const searchSheetIDs = useMemo(
+    () =>
+      debounceAsync(async (uid: string, search: string) => {
+        const ds = (await getDataSourceSrv().get(uid)) as DataSource;
+        return ds.getSpreadSheets(search);
+      }, 300),
+    []
+  );
+
+  // The first page is listed when opened, and the spreadsheets beyond it are found by searching
+  const loadSheetIDs = async (search?: string) => {
     if (!options.uid) {
       return [];
     }
     try {
+      if (search) {
+        return await searchSheetIDs(options.uid, search);
+      }
       const ds = (await getDataSourceSrv().get(options.uid)) as DataSource;
-      return ds.getSpreadSheets();
+      return await ds.getSpreadSheets();
     } catch {
       return [];
     }
@@ -133,6 +162,7 @@ export function ConfigEditor(props: Props) {
       >
         <SegmentAsync
           loadOptions={loadSheetIDs}
+          reloadOptionsOnChange={true}
           placeholder="Select Spreadsheet ID"
           value={selectedSheetOption}
           allowCustomValue={true}
@@ -149,6 +179,71 @@ export function ConfigEditor(props: Props) {
           }}
         />
       </Field>
+
+      <Field label="Root folder ID" description="Optional Drive folder ID restricting the listed spreadsheets">
+        <Input
+          width={40}
+          value={options.jsonData.rootFolderId ?? ''}
+          placeholder="Folder ID"
+          onChange={onUpdateDatasourceJsonDataOption(props, 'rootFolderId')}
+        />
+      </Field>
+
+      <Field label="Include shared drives" description="List the spreadsheets of shared drives too">
+        <InlineSwitch
+          value={options.jsonData.includeSharedDrives ?? false}
+          onChange={onUpdateDatasourceJsonDataOptionChecked(props, 'includeSharedDrives')}
+        />
+      </Field>
+
+      <Field
+        label="Cache size limit (MB)"
+        description="Optional memory budget of the cached spreadsheet data, evicting the least recently used data"
+      >
+        <Input
+          type="number"
+          min={0}
+          width={40}
+          value={options.jsonData.cacheSizeLimitMB ?? ''}
+          placeholder="Unlimited"
+          onChange={(event) =>
+            props.onOptionsChange({
+              ...options,
+              jsonData: {
+                ...options.jsonData,
+                cacheSizeLimitMB: event.currentTarget.value ? Number(event.currentTarget.value) : undefined,
+              },
+            })
+          }
+        />
+      </Field>
+
+      <Field label="Disk cache" description="Keep the cached spreadsheet data on disk across plugin restarts">
+        <InlineSwitch
+          value={options.jsonData.diskCache ?? false}
+          onChange={onUpdateDatasourceJsonDataOptionChecked(props, 'diskCache')}
+        />
+      </Field>
+
+      {options.jsonData.diskCache && (
+        <>
+          <Field
+            label="Encrypt disk cache"
+            description="Encrypt the data written to disk. Without an encryption key, the data is not written to disk."
+          >
+            <InlineSwitch
+              value={options.jsonData.diskCacheEncrypt ?? false}
+              onChange={onUpdateDatasourceJsonDataOptionChecked(props, 'diskCacheEncrypt')}
+            />
+          </Field>
+
+          {options.jsonData.diskCacheEncrypt && (
+            <Field label="Encryption key">
+              <SecretInput {...diskCacheKeyProps} label="Encryption key" width={40} />
+            </Field>
+          )}
+        </>
+      )}
     </>
   );
 }
@@ -4,6 +4,7 @@ import { InlineFieldRow, InlineFormLabel, InlineSwitch, Input, LinkButton, Segme
 import React, { ChangeEvent, PureComponent } from 'react';
 import { DataSource } from '../DataSource';
 import { SheetsQuery } from '../types';
+import { debounceAsync } from '../utils';
 import { reportInteraction } from '@grafana/runtime';
 import { css } from '@emotion/css';
 
@@ -104,6 +105,8 @@ export class QueryEditor extends PureComponent<Props> {
     }
   };
 
+  searchSpreadSheets = debounceAsync((search: string) => this.props.datasource.getSpreadSheets(search), 300);
+
   onRangeChange = (event: ChangeEvent<HTMLInputElement>) => {
     this.props.onChange({
       ...this.props.query,
@@ -165,7 +168,11 @@ export class QueryEditor extends PureComponent<Props> {
             Spreadsheet ID
           </InlineFormLabel>
           <SegmentAsync
-            loadOptions={async () => {
+            loadOptions={async (search?: string) => {
+              // The first page is listed when opened, and the spreadsheets beyond it are found by searching
+              if (search) {
+                return this.searchSpreadSheets(search);
+              }
               const options = await datasource.getSpreadSheets();
               const { query } = this.props;
               const next = resolveSelectedSheetOption(options, query.spreadsheet);
@@ -174,6 +181,7 @@ export class QueryEditor extends PureComponent<Props> {
               }
               return options;
             }}
+            reloadOptionsOnChange={true}
             placeholder="Enter SpreadsheetID"
             value={selectedSheetOption ?? query.spreadsheet}
             allowCustomValue={true}
@@ -15,10 +15,18 @@ export const googleSheetsAuthTypes = [{ label: 'API Key', value: GoogleSheetsAut
 
 export interface GoogleSheetsSecureJSONData extends DataSourceSecureJsonData {
   apiKey?: string;
+  diskCacheKey?: string;
 }
 
 export interface GoogleSheetsDataSourceOptions extends DataSourceOptions {
   defaultSheetID?: string;
+  rootFolderId?: string;
+  includeSharedDrives?: boolean;
+  // Bounds the memory of the cached spreadsheet data, unbounded when unset
+  cacheSizeLimitMB?: number;
+  // Persists the cached spreadsheet data on disk, encrypted with the diskCacheKey secure setting if set
+  diskCache?: boolean;
+  diskCacheEncrypt?: boolean;
 }
 
 export interface CacheInfo {
@@ -35,15 +43,102 @@ export interface SheetResponseMeta {
   warnings: string[];
 }
 
+export interface SpreadsheetInfo {
+  id: string;
+  name: string;
+  owner: string;
+  modifiedTime: string;
+  parentPath: string;
+  webViewLink: string;
+}
+
+export interface SheetInfo {
+  title: string;
+  sheetId: number;
+  index: number;
+  hidden: boolean;
+  rowCount: number;
+  columnCount: number;
+}
+
+export interface NamedRange {
+  name: string;
+  namedRangeId: string;
+  range: string;
+}
+
+export interface ProtectedRange {
+  protectedRangeId: number;
+  description: string;
+  range: string;
+  namedRangeId?: string;
+  warningOnly: boolean;
+}
+
+export interface ColumnSchema {
+  header: string;
+  type: 'TIME' | 'NUMBER' | 'STRING' | 'BOOLEAN';
+  unit: string;
+  mixedTypes: boolean;
+  mixedUnits: boolean;
+  samples: string[];
+}
+
+export interface RangesInfo {
+  namedRanges: NamedRange[];
+  protectedRanges: ProtectedRange[];
+}
+
 //-------------------------------------------------------------------------------
 // The Sheets specific types
 //-------------------------------------------------------------------------------
 
+// Query types returning metadata instead of the grid data of the spreadsheet
+export type SheetsQueryType = 'variable' | 'spreadsheets' | 'sheets' | 'fileInfo';
+
 export interface SheetsQuery extends DataQuery {
+  queryType?: SheetsQueryType;
   spreadsheet: string;
   range?: string;
+  ranges?: string[];
   cacheDurationSeconds?: number;
   useTimeFilter?: boolean;
+  // Serve the cache only while the Drive modified time of the spreadsheet is unchanged,
+  // or serve it stale for up to maxStaleSeconds after expiry while it is refreshed
+  cacheMode?: 'ttl' | 'modifiedTime' | 'staleWhileRevalidate';
+  maxStaleSeconds?: number;
+  fetchMode?: 'grid' | 'values';
+  unionSheets?: string;
+  headerRowIndex?: number;
+  headerRowCount?: number;
+  noHeader?: boolean;
+  columnTypes?: Record<string, 'time' | 'number' | 'string' | 'boolean'>;
+  timeFormat?: string;
+  timezone?: string;
+  filters?: SheetsFilter[];
+  groupBy?: string[];
+  groupByInterval?: string;
+  aggregations?: SheetsAggregation[];
+  downsample?: boolean;
+  downsampleFunctions?: Record<string, SheetsAggregation['function']>;
+  freshness?: boolean;
+  freshnessFrame?: boolean;
+  stream?: boolean;
+  streamIntervalSeconds?: number;
+}
+
+export interface SheetsAggregation {
+  column: string;
+  function: 'sum' | 'avg' | 'min' | 'max' | 'count' | 'first' | 'last';
+  alias?: string;
+}
+
+export type SheetsFilterOperator = '=' | '!=' | '<' | '<=' | '>' | '>=' | 'contains' | 'regex' | 'in';
+
+export interface SheetsFilter {
+  column: string;
+  operator: SheetsFilterOperator;
+  value: string;
 }
 
 export interface SheetsVariableQuery extends SheetsQuery {
@@ -51,4 +146,5 @@ export interface SheetsVariableQuery extends SheetsQuery {
   labelField?: string;
   filterField?: string;
   filterValue?: string;
+  variableSort?: 'asc' | 'desc';
 }
@@ -1,5 +1,5 @@
 import { GoogleSheetsAuth } from './types';
-import { getBackwardCompatibleOptions } from './utils';
+import { debounceAsync, getBackwardCompatibleOptions } from './utils';
 
 describe('getBackwardCompatibleOptions', () => {
   it('should not mutate the option object', () => {
@@ -56,3 +56,22 @@ describe('getBackwardCompatibleOptions', () => {
     expect(getBackwardCompatibleOptions(options)).toEqual(expectedOptions);
   });
 });
+
+describe('debounceAsync', () => {
+  beforeEach(() => jest.useFakeTimers());
+  afterEach(() => jest.useRealTimers());
+
+  it('should call the function once with the last arguments', async () => {
+    const fn = jest.fn((search: string) => Promise.resolve(search.toUpperCase()));
+    const debounced = debounceAsync(fn, 300);
+
+    const first = debounced('sal');
+    const last = debounced('sales');
+    jest.advanceTimersByTime(300);
+
+    await expect(first).resolves.toBe('SALES');
+    await expect(last).resolves.toBe('SALES');
+    expect(fn).toHaveBeenCalledTimes(1);
+    expect(fn).toHaveBeenCalledWith('sales');
+  });
+});
@@ -20,3 +20,26 @@ export function getBackwardCompatibleOptions(options: Props['options']): Props['
 
   return changedOptions;
 }
+
+// debounceAsync returns a function calling fn once no call was made for the wait, in milliseconds. The calls
+// made meanwhile all get the result of the last one, such as the options of the last search term typed.
+export function debounceAsync<A extends unknown[], R>(
+  fn: (...args: A) => Promise<R>,
+  wait: number
+): (...args: A) => Promise<R> {
+  let timeout: ReturnType<typeof setTimeout> | undefined;
+  let pending: Array<{ resolve: (value: R) => void; reject: (reason: unknown) => void }> = [];
+  return (...args: A) =>
+    new Promise<R>((resolve, reject) => {
+      clearTimeout(timeout);
+      pending.push({ resolve, reject });
+      timeout = setTimeout(() => {
+        const callers = pending;
+        pending = [];
+        fn(...args).then(
+          (value) => callers.forEach((caller) => caller.resolve(value)),
+          (reason) => callers.forEach((caller) => caller.reject(reason))
+        );
+      }, wait);
+    });
+}
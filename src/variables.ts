@@ -2,7 +2,9 @@ import { map, Observable } from 'rxjs';
 import { CustomVariableSupport, DataQueryResponse, DataQueryRequest, Field } from '@grafana/data';
 import { DataSource } from './DataSource';
 import VariableQueryEditor from './components/VariableQueryEditor';
-import type { SheetsVariableQuery } from './types';
+import type { SheetsQueryType, SheetsVariableQuery } from './types';
+
+export const VARIABLE_QUERY_TYPE: SheetsQueryType = 'variable';
 
 export class SheetsVariableSupport extends CustomVariableSupport<DataSource, SheetsVariableQuery> {
   constructor(private readonly datasource: DataSource) {
@@ -12,12 +14,14 @@ export class SheetsVariableSupport extends CustomVariableSupport<DataSource, She
   }
   editor = VariableQueryEditor;
   query(request: DataQueryRequest<SheetsVariableQuery>): Observable<DataQueryResponse> {
-    let query = { ...request?.targets[0], refId: 'metricFindQuery' };
+    let query = { ...request?.targets[0], refId: 'metricFindQuery', queryType: VARIABLE_QUERY_TYPE };
     let interpolatedQuery = this.datasource.interpolateVariableQuery(query, request.scopedVars);
+    // The backend returns the distinct, filtered and sorted values in value and text fields
+    const backendVariableQuery = { ...query, valueField: 'value', labelField: 'text', filterField: undefined };
     return this.datasource
       .query({ ...request, targets: [interpolatedQuery] })
       .pipe(map((response) => ({ ...response, data: response.data || [] })))
-      .pipe(map((response) => queryResponseToVariablesFrame(interpolatedQuery, response)));
+      .pipe(map((response) => queryResponseToVariablesFrame(backendVariableQuery, response)));
   }
 }
 